@@ -0,0 +1,518 @@
+// Aggregate accumulators
+//
+// Accumulators keep running state of aggregate functions over a column.
+// Intermediate sums are stored in units wider than FixedDecimal, with
+// fixed MaxFracUnits fractional units, so a sum never overflows
+// in the middle and only the final result is checked against MaxUnits.
+//
+// All accumulators support Add, Remove for sliding windows, Merge for
+// parallel partial aggregation and Result. Special values are rejected
+// with DecErrInvalidValue, and accumulator is unchanged on error.
+// Result of empty accumulator follows SQL semantics where possible,
+// callers should check Count to produce NULL.
+package fxd
+
+import "container/heap"
+
+const (
+	// aggValueUnits is number of units to store a decimal with
+	// MaxFracUnits fractional units.
+	aggValueUnits = MaxUnits + MaxFracUnits
+	// aggScale is scale of intermediate sums.
+	aggScale = MaxFracUnits * DigitsPerUnit
+	// sumPrecIncr is precision increment of MySQL SUM over decimal.
+	sumPrecIncr = 22
+	// varUnits is number of units of count times sum of squares,
+	// count has at most 3 units.
+	varUnits = 2*DoubleMaxUnits + 3
+)
+
+// SumResultType returns precision and scale of MySQL SUM result
+// over DECIMAL(prec, scale).
+func SumResultType(prec, scale int) (int, int) {
+	return minInt(prec+sumPrecIncr, MaxDigits), scale
+}
+
+// AvgResultType returns precision and scale of MySQL AVG result
+// over DECIMAL(prec, scale), with default div_precision_increment
+// which is same as DivIncrFrac.
+func AvgResultType(prec, scale int) (int, int) {
+	return minInt(prec+DivIncrFrac, MaxDigits), minInt(scale+DivIncrFrac, MaxFrac)
+}
+
+// SumAcc accumulates sum of decimals.
+// Zero value is an empty accumulator.
+type SumAcc struct {
+	pos   [DoubleMaxUnits]int32 // sum of added positive and removed negative values
+	neg   [DoubleMaxUnits]int32 // sum of added negative and removed positive values
+	frac  int8                  // maximum frac of all values
+	count int64
+}
+
+// Add adds a decimal to the sum.
+func (a *SumAcc) Add(fd *FixedDecimal) error {
+	return a.update(fd, false)
+}
+
+// Remove removes a previously added decimal from the sum.
+func (a *SumAcc) Remove(fd *FixedDecimal) error {
+	return a.update(fd, true)
+}
+
+func (a *SumAcc) update(fd *FixedDecimal, remove bool) error {
+	if fd.IsNaN() || fd.IsInf() {
+		return DecErrInvalidValue
+	}
+	var buf [aggValueUnits]int32
+	units := aggUnits(fd, &buf)
+	acc := &a.pos
+	if fd.IsNeg() != remove {
+		acc = &a.neg
+	}
+	saved := *acc
+	if !unitsAdd(acc[:], units) {
+		*acc = saved
+		return DecErrOverflow
+	}
+	if remove {
+		a.count--
+	} else {
+		a.count++
+	}
+	a.frac = maxInt8(a.frac, fd.Frac())
+	return nil
+}
+
+// Merge merges partial sum of other accumulator.
+func (a *SumAcc) Merge(other *SumAcc) error {
+	saved := *a
+	if !unitsAdd(a.pos[:], other.pos[:]) || !unitsAdd(a.neg[:], other.neg[:]) {
+		*a = saved
+		return DecErrOverflow
+	}
+	a.count += other.count
+	a.frac = maxInt8(a.frac, other.frac)
+	return nil
+}
+
+// Count returns number of values in the accumulator.
+func (a *SumAcc) Count() int64 {
+	return a.count
+}
+
+// Result returns the sum, with maximum frac of all values.
+// Sum of empty accumulator is zero.
+// Returns DecErrOverflow if the sum exceeds MaxDigits.
+func (a *SumAcc) Result() (FixedDecimal, error) {
+	var coef [DoubleMaxUnits]int32
+	neg := a.signedSum(&coef)
+	coefDivPow10(coef[:], aggScale-int(a.frac)) // exact
+	var res FixedDecimal
+	err := res.setCoefUnits(coef[:], int(a.frac), neg)
+	return res, err
+}
+
+// signedSum stores absolute value of the sum to coef, returns true if
+// the sum is negative.
+func (a *SumAcc) signedSum(coef *[DoubleMaxUnits]int32) bool {
+	if unitsCmp(a.pos[:], a.neg[:]) >= 0 {
+		*coef = a.pos
+		unitsSub(coef[:], a.neg[:])
+		return false
+	}
+	*coef = a.neg
+	unitsSub(coef[:], a.pos[:])
+	return true
+}
+
+// AvgAcc accumulates average of decimals.
+// Zero value is an empty accumulator.
+type AvgAcc struct {
+	sum SumAcc
+}
+
+// Add adds a decimal to the average.
+func (a *AvgAcc) Add(fd *FixedDecimal) error {
+	return a.sum.Add(fd)
+}
+
+// Remove removes a previously added decimal from the average.
+func (a *AvgAcc) Remove(fd *FixedDecimal) error {
+	return a.sum.Remove(fd)
+}
+
+// Merge merges partial average of other accumulator.
+func (a *AvgAcc) Merge(other *AvgAcc) error {
+	return a.sum.Merge(&other.sum)
+}
+
+// Count returns number of values in the accumulator.
+func (a *AvgAcc) Count() int64 {
+	return a.sum.count
+}
+
+// Result returns the average with DivIncrFrac more fractional digits
+// than maximum frac of all values, rounded half up, as MySQL AVG.
+// Returns DecErrDivisionByZero if the accumulator is empty.
+func (a *AvgAcc) Result() (FixedDecimal, error) {
+	var res FixedDecimal
+	if a.sum.count <= 0 {
+		return res, DecErrDivisionByZero
+	}
+	var coef [DoubleMaxUnits]int32
+	neg := a.sum.signedSum(&coef)
+	// truncated quotient with aggScale is enough for rounding to smaller scale
+	unitsDivRem64(coef[:], uint64(a.sum.count))
+	scale := minInt(int(a.sum.frac)+DivIncrFrac, MaxFrac)
+	coefDivPow10HalfUp(coef[:], aggScale-scale)
+	err := res.setCoefUnits(coef[:], scale, neg)
+	return res, err
+}
+
+// VarianceAcc accumulates variance of decimals.
+// Population variance is computed by default, set Sample to compute
+// sample variance.
+type VarianceAcc struct {
+	Sample bool
+	sum    SumAcc
+	sqsum  [2 * DoubleMaxUnits]int32 // sum of squares, with 2*MaxFracUnits fractional units
+}
+
+// Add adds a decimal to the variance.
+func (a *VarianceAcc) Add(fd *FixedDecimal) error {
+	saved := *a
+	if err := a.sum.Add(fd); err != nil {
+		return err
+	}
+	var sq [2 * aggValueUnits]int32
+	if !unitsAdd(a.sqsum[:], aggSquare(fd, &sq)) {
+		*a = saved
+		return DecErrOverflow
+	}
+	return nil
+}
+
+// Remove removes a previously added decimal from the variance.
+func (a *VarianceAcc) Remove(fd *FixedDecimal) error {
+	if err := a.sum.Remove(fd); err != nil {
+		return err
+	}
+	var sq [2 * aggValueUnits]int32
+	unitsSub(a.sqsum[:], aggSquare(fd, &sq))
+	return nil
+}
+
+// Merge merges partial variance of other accumulator.
+func (a *VarianceAcc) Merge(other *VarianceAcc) error {
+	saved := *a
+	if err := a.sum.Merge(&other.sum); err != nil {
+		return err
+	}
+	if !unitsAdd(a.sqsum[:], other.sqsum[:]) {
+		*a = saved
+		return DecErrOverflow
+	}
+	return nil
+}
+
+// Count returns number of values in the accumulator.
+func (a *VarianceAcc) Count() int64 {
+	return a.sum.count
+}
+
+// Result returns the variance with same scale as average,
+// rounded half up.
+// Returns DecErrDivisionByZero if there is no value, or only one value
+// for sample variance.
+func (a *VarianceAcc) Result() (FixedDecimal, error) {
+	return a.result(a.Sample, false)
+}
+
+// result computes variance, or standard deviation if sqrt is true.
+func (a *VarianceAcc) result(sample, sqrt bool) (FixedDecimal, error) {
+	var res FixedDecimal
+	n := a.sum.count
+	if n <= 0 || sample && n == 1 {
+		return res, DecErrDivisionByZero
+	}
+	// variance = (n*sqsum - sum*sum) / (n*n) or (n*sqsum - sum*sum) / (n*(n-1)),
+	// where numerator is integer scaled by 10^(2*aggScale)
+	var coef [DoubleMaxUnits]int32
+	a.sum.signedSum(&coef)
+	var count [MaxUnits]int32
+	k := int64Units(uint64(n), &count)
+	var num, sq [varUnits]int32
+	unitsMul(num[:len(a.sqsum)+k], a.sqsum[:], count[:k])
+	unitsMul(sq[:2*DoubleMaxUnits], coef[:], coef[:])
+	unitsSub(num[:], sq[:])
+	scale := minInt(int(a.sum.frac)+DivIncrFrac, MaxFrac)
+	// compute with one more digit, then round half up
+	exp := scale + 1
+	if sqrt {
+		exp *= 2
+	}
+	// truncating by each factor of denominator in turn is same as
+	// truncating by the product
+	coefDivPow10(num[:], 2*aggScale-exp)
+	unitsDivRem64(num[:], uint64(n))
+	if sample {
+		n--
+	}
+	unitsDivRem64(num[:], uint64(n))
+	if sqrt {
+		var root [varUnits]int32
+		unitsSqrt(num[:], root[:])
+		num = root
+	}
+	coefDivPow10HalfUp(num[:], 1)
+	err := res.setCoefUnits(num[:], scale, false)
+	return res, err
+}
+
+// StdDevAcc accumulates standard deviation of decimals.
+// Population standard deviation is computed by default, set Sample to
+// compute sample standard deviation.
+type StdDevAcc struct {
+	Sample bool
+	v      VarianceAcc
+}
+
+// Add adds a decimal to the standard deviation.
+func (a *StdDevAcc) Add(fd *FixedDecimal) error {
+	return a.v.Add(fd)
+}
+
+// Remove removes a previously added decimal from the standard deviation.
+func (a *StdDevAcc) Remove(fd *FixedDecimal) error {
+	return a.v.Remove(fd)
+}
+
+// Merge merges partial standard deviation of other accumulator.
+func (a *StdDevAcc) Merge(other *StdDevAcc) error {
+	return a.v.Merge(&other.v)
+}
+
+// Count returns number of values in the accumulator.
+func (a *StdDevAcc) Count() int64 {
+	return a.v.sum.count
+}
+
+// Result returns the standard deviation with same scale as average,
+// rounded half up.
+// Returns DecErrDivisionByZero if there is no value, or only one value
+// for sample standard deviation.
+func (a *StdDevAcc) Result() (FixedDecimal, error) {
+	return a.v.result(a.Sample, true)
+}
+
+// MinAcc accumulates minimum of decimals.
+// To support Remove, number of occurrences of each distinct value is
+// kept, so memory usage grows with distinct values.
+type MinAcc struct {
+	extremeAcc
+}
+
+// MaxAcc accumulates maximum of decimals.
+// To support Remove, number of occurrences of each distinct value is
+// kept, so memory usage grows with distinct values.
+type MaxAcc struct {
+	extremeAcc
+}
+
+// Add adds a decimal to the minimum.
+func (a *MinAcc) Add(fd *FixedDecimal) error {
+	return a.add(fd, -1)
+}
+
+// Remove removes a previously added decimal from the minimum.
+// Returns DecErrInvalidValue if the decimal is not in the accumulator.
+func (a *MinAcc) Remove(fd *FixedDecimal) error {
+	return a.remove(fd)
+}
+
+// Merge merges partial minimum of other accumulator.
+func (a *MinAcc) Merge(other *MinAcc) error {
+	return a.merge(&other.extremeAcc, -1)
+}
+
+// Add adds a decimal to the maximum.
+func (a *MaxAcc) Add(fd *FixedDecimal) error {
+	return a.add(fd, 1)
+}
+
+// Remove removes a previously added decimal from the maximum.
+// Returns DecErrInvalidValue if the decimal is not in the accumulator.
+func (a *MaxAcc) Remove(fd *FixedDecimal) error {
+	return a.remove(fd)
+}
+
+// Merge merges partial maximum of other accumulator.
+func (a *MaxAcc) Merge(other *MaxAcc) error {
+	return a.merge(&other.extremeAcc, 1)
+}
+
+// extremeEntry is a distinct value and its number of occurrences.
+type extremeEntry struct {
+	value FixedDecimal // first added representation
+	n     int64
+	index int // index in heap
+}
+
+// extremeHeap is a binary heap of distinct values, with minimum on top
+// if dir is -1, or maximum if dir is 1.
+type extremeHeap struct {
+	entries []*extremeEntry
+	dir     int
+}
+
+func (h *extremeHeap) Len() int {
+	return len(h.entries)
+}
+
+func (h *extremeHeap) Less(i, j int) bool {
+	return h.entries[i].value.cmpFinite(&h.entries[j].value) == h.dir
+}
+
+func (h *extremeHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].index = i
+	h.entries[j].index = j
+}
+
+func (h *extremeHeap) Push(x interface{}) {
+	e := x.(*extremeEntry)
+	e.index = len(h.entries)
+	h.entries = append(h.entries, e)
+}
+
+func (h *extremeHeap) Pop() interface{} {
+	n := len(h.entries) - 1
+	e := h.entries[n]
+	h.entries[n] = nil
+	h.entries = h.entries[:n]
+	return e
+}
+
+// extremeAcc tracks minimum if dir is -1, or maximum if dir is 1.
+// Distinct values are looked up by canonical form, and kept in a heap,
+// so Add and Remove take logarithmic time.
+type extremeAcc struct {
+	entries map[FixedDecimal]*extremeEntry // keyed by canonical form
+	heap    extremeHeap
+	count   int64
+}
+
+// Count returns number of values in the accumulator.
+func (a *extremeAcc) Count() int64 {
+	return a.count
+}
+
+// Result returns the extreme value with its original frac.
+// Returns DecErrInvalidValue if the accumulator is empty.
+func (a *extremeAcc) Result() (FixedDecimal, error) {
+	if len(a.heap.entries) == 0 {
+		return FixedDecimal{}, DecErrInvalidValue
+	}
+	return a.heap.entries[0].value, nil
+}
+
+func (a *extremeAcc) add(fd *FixedDecimal, dir int) error {
+	if fd.IsNaN() || fd.IsInf() {
+		return DecErrInvalidValue
+	}
+	return a.addN(fd, 1, dir)
+}
+
+func (a *extremeAcc) addN(fd *FixedDecimal, n int64, dir int) error {
+	if a.entries == nil {
+		a.entries = make(map[FixedDecimal]*extremeEntry)
+		a.heap.dir = dir
+	}
+	key := fd.Canonical()
+	e, ok := a.entries[key]
+	if !ok {
+		e = &extremeEntry{value: *fd}
+		a.entries[key] = e
+		heap.Push(&a.heap, e)
+	}
+	e.n += n
+	a.count += n
+	return nil
+}
+
+func (a *extremeAcc) remove(fd *FixedDecimal) error {
+	if fd.IsNaN() || fd.IsInf() {
+		return DecErrInvalidValue
+	}
+	key := fd.Canonical()
+	e, ok := a.entries[key]
+	if !ok {
+		return DecErrInvalidValue
+	}
+	e.n--
+	a.count--
+	if e.n == 0 {
+		delete(a.entries, key)
+		heap.Remove(&a.heap, e.index)
+	}
+	return nil
+}
+
+func (a *extremeAcc) merge(other *extremeAcc, dir int) error {
+	for _, e := range other.heap.entries {
+		if err := a.addN(&e.value, e.n, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggUnits returns units of absolute value of fd with MaxFracUnits
+// fractional units.
+func aggUnits(fd *FixedDecimal, buf *[aggValueUnits]int32) []int32 {
+	iu, fu := fd.IntgUnits(), fd.FracUnits()
+	copy(buf[MaxFracUnits-fu:], fd.lsu[:iu+fu])
+	return buf[:MaxFracUnits+iu]
+}
+
+// aggSquare returns units of square of fd with 2*MaxFracUnits
+// fractional units.
+func aggSquare(fd *FixedDecimal, buf *[2 * aggValueUnits]int32) []int32 {
+	var vbuf [aggValueUnits]int32
+	units := aggUnits(fd, &vbuf)
+	res := buf[:2*len(units)]
+	unitsMul(res, units, units)
+	return res
+}
+
+// unitsSqrt stores truncated square root of little-endian units x to
+// root, computed digit by digit. x must have at most varUnits units.
+func unitsSqrt(x []int32, root []int32) {
+	var rem, t [varUnits + 1]int32
+	for i := range root {
+		root[i] = 0
+	}
+	digit := func(i int) int64 {
+		if i >= len(x)*DigitsPerUnit {
+			return 0
+		}
+		return int64(unitsDigit(x, i))
+	}
+	for i := (unitsDigits(x)+1)/2 - 1; i >= 0; i-- {
+		// bring down next pair of digits
+		unitsMulAdd(rem[:], 100, digit(2*i+1)*10+digit(2*i))
+		// largest d such that (20*root+d)*d <= rem
+		d := int64(9)
+		for ; d > 0; d-- {
+			t = [varUnits + 1]int32{}
+			copy(t[:], root)
+			unitsMulAdd(t[:], 20, d)
+			unitsMulAdd(t[:], d, 0)
+			if unitsCmp(t[:], rem[:]) <= 0 {
+				unitsSub(rem[:], t[:])
+				break
+			}
+		}
+		unitsMulAdd(root, 10, d)
+	}
+}
@@ -0,0 +1,308 @@
+package fxd
+
+import "testing"
+
+func TestDecimalAggregate(t *testing.T) {
+	values := []string{"1.5", "-2.25", "3", "10.125", "0.001"}
+	var sum SumAcc
+	var avg AvgAcc
+	var min MinAcc
+	var max MaxAcc
+	varPop, varSamp := VarianceAcc{}, VarianceAcc{Sample: true}
+	sdPop, sdSamp := StdDevAcc{}, StdDevAcc{Sample: true}
+	type acc interface {
+		Add(*FixedDecimal) error
+		Count() int64
+		Result() (FixedDecimal, error)
+	}
+	type tcase struct {
+		name     string
+		acc      acc
+		expected string
+	}
+	cases := []tcase{
+		{"sum", &sum, "12.376"},
+		{"avg", &avg, "2.4752000"},
+		{"min", &min, "-2.25"},
+		{"max", &max, "10.125"},
+		{"var_pop", &varPop, "17.6390102"},
+		{"var_samp", &varSamp, "22.0487627"},
+		{"stddev_pop", &sdPop, "4.1998822"},
+		{"stddev_samp", &sdSamp, "4.6956110"},
+	}
+	for _, s := range values {
+		fd := mustParse(s)
+		for _, c := range cases {
+			if err := c.acc.Add(&fd); err != nil {
+				t.Fatalf("%v add %v failed: %v", c.name, s, err)
+			}
+		}
+	}
+	for _, c := range cases {
+		res, err := c.acc.Result()
+		if err != nil || res.ToString(-1) != c.expected || c.acc.Count() != int64(len(values)) {
+			t.Fatalf("%v mismatch: actual=%v,%v, expected=%v", c.name, res.ToString(-1), err, c.expected)
+		}
+	}
+}
+
+func TestDecimalAggregateEmpty(t *testing.T) {
+	var sum SumAcc
+	if res, err := sum.Result(); err != nil || res.ToString(-1) != "0" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	var avg AvgAcc
+	if _, err := avg.Result(); err != DecErrDivisionByZero {
+		t.Fatalf("failed %v", err)
+	}
+	var min MinAcc
+	if _, err := min.Result(); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	v := VarianceAcc{Sample: true}
+	one := mustParse("1")
+	v.Add(&one)
+	if _, err := v.Result(); err != DecErrDivisionByZero {
+		t.Fatalf("failed %v", err)
+	}
+	v.Sample = false
+	if res, err := v.Result(); err != nil || res.ToString(-1) != "0.0000" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	nan := parseSpecial("NaN")
+	if err := sum.Add(&nan); err != DecErrInvalidValue || sum.Count() != 0 {
+		t.Fatalf("failed %v", err)
+	}
+	if err := min.Add(&nan); err != DecErrInvalidValue || min.Count() != 0 {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecimalAggregateWide(t *testing.T) {
+	max := mustParse("99999999999999999999999999999999999999999999999999999999999999999")
+	tiny := mustParse("-0.000000000000000000000000000001")
+	var sum SumAcc
+	var avg AvgAcc
+	for i := 0; i < 10; i++ {
+		sum.Add(&max)
+		avg.Add(&max)
+	}
+	if _, err := sum.Result(); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if res, err := avg.Result(); err != nil || res.ToString(-1) != max.ToString(-1)+".0000" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	for i := 0; i < 9; i++ {
+		sum.Remove(&max)
+	}
+	if res, err := sum.Result(); err != nil || res != max {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	sum.Remove(&max)
+	sum.Add(&tiny)
+	if res, err := sum.Result(); err != nil || res.ToString(-1) != tiny.ToString(-1) {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	// frac of result is maximum frac of values, even after removal
+	sum.Remove(&tiny)
+	if res, err := sum.Result(); err != nil || res.ToString(-1) != "0.000000000000000000000000000000" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	// accumulator is unchanged on overflow
+	var v VarianceAcc
+	one := mustParse("1")
+	v.Add(&one)
+	for i := range v.sqsum {
+		v.sqsum[i] = Unit - 1
+	}
+	saved := v
+	if err := v.Add(&one); err != DecErrOverflow || v != saved {
+		t.Fatalf("failed %v", err)
+	}
+	for i := range v.sum.pos {
+		v.sum.pos[i] = Unit - 1
+	}
+	saved = v
+	if err := v.Add(&one); err != DecErrOverflow || v != saved {
+		t.Fatalf("failed %v", err)
+	}
+	if err := v.Merge(&saved); err != DecErrOverflow || v != saved {
+		t.Fatalf("failed %v", err)
+	}
+	// rounding of average
+	avg = AvgAcc{}
+	for _, s := range []string{"-2", "0", "0"} {
+		fd := mustParse(s)
+		avg.Add(&fd)
+	}
+	if res, err := avg.Result(); err != nil || res.ToString(-1) != "-0.6667" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+}
+
+func TestDecimalAggregateWindow(t *testing.T) {
+	// same frac for all values, so results of window and recomputation have same scale
+	values := []string{"3.500", "-1.000", "7.250", "7.250", "0.000", "-4.125", "2.000", "9.500", "-1.000"}
+	const width = 3
+	var sum SumAcc
+	var avg AvgAcc
+	var min MinAcc
+	var max MaxAcc
+	var sd StdDevAcc
+	for i, s := range values {
+		fd := mustParse(s)
+		sum.Add(&fd)
+		avg.Add(&fd)
+		min.Add(&fd)
+		max.Add(&fd)
+		sd.Add(&fd)
+		if i >= width {
+			old := mustParse(values[i-width])
+			sum.Remove(&old)
+			avg.Remove(&old)
+			min.Remove(&old)
+			max.Remove(&old)
+			sd.Remove(&old)
+		}
+		// compare with accumulators of current window
+		var wsum SumAcc
+		var wavg AvgAcc
+		var wmin MinAcc
+		var wmax MaxAcc
+		var wsd StdDevAcc
+		for j := i - width + 1; j <= i; j++ {
+			if j < 0 {
+				continue
+			}
+			fd := mustParse(values[j])
+			wsum.Add(&fd)
+			wavg.Add(&fd)
+			wmin.Add(&fd)
+			wmax.Add(&fd)
+			wsd.Add(&fd)
+		}
+		for _, pair := range [][2]interface{ Result() (FixedDecimal, error) }{
+			{&sum, &wsum}, {&avg, &wavg}, {&min, &wmin}, {&max, &wmax}, {&sd, &wsd},
+		} {
+			actual, _ := pair[0].Result()
+			expected, _ := pair[1].Result()
+			if !actual.Equal(&expected) {
+				t.Fatalf("window %v mismatch: actual=%v, expected=%v", i, actual.ToString(-1), expected.ToString(-1))
+			}
+		}
+	}
+	if min.Count() != width {
+		t.Fatalf("failed %v", min.Count())
+	}
+	absent := mustParse("100")
+	if err := max.Remove(&absent); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecimalAggregateExtreme(t *testing.T) {
+	var values []FixedDecimal
+	var min MinAcc
+	var max MaxAcc
+	for i := 0; i < 50; i++ {
+		fd := DecimalFromInt64(int64(i*37%23 - 11))
+		values = append(values, fd)
+		min.Add(&fd)
+		max.Add(&fd)
+	}
+	// remove values in order and compare with remaining ones
+	for i := range values {
+		lo, hi := values[i], values[i]
+		for j := i + 1; j < len(values); j++ {
+			if values[j].Compare(&lo) < 0 {
+				lo = values[j]
+			}
+			if values[j].Compare(&hi) > 0 {
+				hi = values[j]
+			}
+		}
+		if res, err := min.Result(); err != nil || !res.Equal(&lo) {
+			t.Fatalf("min %v mismatch: actual=%v, expected=%v", i, res.ToString(-1), lo.ToString(-1))
+		}
+		if res, err := max.Result(); err != nil || !res.Equal(&hi) {
+			t.Fatalf("max %v mismatch: actual=%v, expected=%v", i, res.ToString(-1), hi.ToString(-1))
+		}
+		if err := min.Remove(&values[i]); err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if err := max.Remove(&values[i]); err != nil {
+			t.Fatalf("failed %v", err)
+		}
+	}
+	if _, err := max.Result(); err != DecErrInvalidValue || max.Count() != 0 {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecimalAggregateMerge(t *testing.T) {
+	values := []string{"3.50", "-1", "7.25", "7.250", "0", "-4.125", "2", "9.5", "-1"}
+	var sum, sum1, sum2 SumAcc
+	var v, v1, v2 VarianceAcc
+	var max, max1, max2 MaxAcc
+	for i, s := range values {
+		fd := mustParse(s)
+		sum.Add(&fd)
+		v.Add(&fd)
+		max.Add(&fd)
+		if i%2 == 0 {
+			sum1.Add(&fd)
+			v1.Add(&fd)
+			max1.Add(&fd)
+		} else {
+			sum2.Add(&fd)
+			v2.Add(&fd)
+			max2.Add(&fd)
+		}
+	}
+	if err := sum1.Merge(&sum2); err != nil {
+		t.Fatal("failed")
+	}
+	if err := v1.Merge(&v2); err != nil {
+		t.Fatal("failed")
+	}
+	if err := max1.Merge(&max2); err != nil {
+		t.Fatal("failed")
+	}
+	for _, pair := range [][2]interface{ Result() (FixedDecimal, error) }{
+		{&sum, &sum1}, {&v, &v1}, {&max, &max1},
+	} {
+		expected, _ := pair[0].Result()
+		actual, _ := pair[1].Result()
+		if actual != expected {
+			t.Fatalf("merge mismatch: actual=%v, expected=%v", actual.ToString(-1), expected.ToString(-1))
+		}
+	}
+	// first added representation is kept
+	if res, _ := max1.Result(); res.ToString(-1) != "9.5" || sum1.Count() != int64(len(values)) {
+		t.Fatalf("failed %v", res.ToString(-1))
+	}
+}
+
+func TestDecimalAggregateResultType(t *testing.T) {
+	type tcase struct {
+		prec, scale       int
+		sumPrec, sumScale int
+		avgPrec, avgScale int
+	}
+	for _, c := range []tcase{
+		{10, 2, 32, 2, 14, 6},
+		{50, 10, 65, 10, 54, 14},
+		{65, 30, 65, 30, 65, 30},
+		{5, 28, 27, 28, 9, 30},
+	} {
+		p, s := SumResultType(c.prec, c.scale)
+		if p != c.sumPrec || s != c.sumScale {
+			t.Fatalf("sum type (%v,%v) mismatch: actual=(%v,%v)", c.prec, c.scale, p, s)
+		}
+		p, s = AvgResultType(c.prec, c.scale)
+		if p != c.avgPrec || s != c.avgScale {
+			t.Fatalf("avg type (%v,%v) mismatch: actual=(%v,%v)", c.prec, c.scale, p, s)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Allocation of a total into parts
+//
+// Dividing an amount, e.g. $100.00 three ways, with DecimalDiv and
+// rounding each quotient does not guarantee that the parts sum to the
+// amount. Allocate and Split compute each part at given scale by
+// truncating its exact share, then distribute the remaining minimal
+// units (10^-scale each) by AllocPolicy, so the parts always sum
+// exactly to the total.
+// A negative total is allocated as its absolute value with all parts
+// negated, so allocation is symmetric around zero.
+package fxd
+
+import "sort"
+
+// AllocPolicy decides which parts receive the remaining minimal units.
+type AllocPolicy uint8
+
+const (
+	AllocLargestRemainder AllocPolicy = iota // parts with largest truncated remainder first, ties go to earlier part
+	AllocRoundRobin                          // one unit per part, in order of parts
+)
+
+// Allocate divides total into parts proportional to ratios, with
+// scale fractional digits each.
+// Ratios must be non-negative and not all zero, and a part of zero ratio
+// is always zero. total must be exact at scale, otherwise the parts
+// cannot sum to it and DecErrInvalidValue is returned.
+func Allocate(total *FixedDecimal, ratios []FixedDecimal, scale int, policy AllocPolicy) ([]FixedDecimal, error) {
+	if len(ratios) == 0 {
+		return nil, DecErrInvalidValue
+	}
+	bufs := make([][aggValueUnits]int32, len(ratios))
+	weights := make([][]int32, len(ratios))
+	for i := range ratios {
+		r := &ratios[i]
+		if r.IsNaN() || r.IsInf() || (r.IsNeg() && !r.allUnitsZero()) {
+			return nil, DecErrInvalidValue
+		}
+		weights[i] = aggUnits(r, &bufs[i])
+	}
+	return allocate(total, weights, scale, policy)
+}
+
+// Split divides total into n equal parts, with scale fractional digits
+// each. Remaining minimal units go to the first parts with either policy.
+func Split(total *FixedDecimal, n int, scale int, policy AllocPolicy) ([]FixedDecimal, error) {
+	if n <= 0 {
+		return nil, DecErrInvalidValue
+	}
+	one := []int32{1}
+	weights := make([][]int32, n)
+	for i := range weights {
+		weights[i] = one
+	}
+	return allocate(total, weights, scale, policy)
+}
+
+// allocate divides total into parts proportional to non-negative
+// integer weights, as little-endian units of at most aggValueUnits.
+func allocate(total *FixedDecimal, weights [][]int32, scale int, policy AllocPolicy) ([]FixedDecimal, error) {
+	if total.IsNaN() || total.IsInf() || scale < 0 || scale > MaxFrac {
+		return nil, DecErrInvalidValue
+	}
+	// weights have at most aggValueUnits units, so the sum of all weights
+	// of any slice fits DoubleMaxUnits
+	var sum [DoubleMaxUnits]int32
+	for _, w := range weights {
+		unitsAdd(sum[:], w)
+	}
+	if !unitsNonZero(sum[:]) {
+		return nil, DecErrInvalidValue
+	}
+	// number of minimal units of total
+	var amount [aggValueUnits]int32
+	aggUnits(total, &amount)
+	if coefDivPow10(amount[:], aggScale-scale) {
+		return nil, DecErrInvalidValue
+	}
+	shares := make([][aggValueUnits]int32, len(weights))
+	rems := make([][DoubleMaxUnits]int32, len(weights))
+	left := amount
+	for i, w := range weights {
+		var prod, q [2 * DoubleMaxUnits]int32
+		unitsMul(prod[:aggValueUnits+len(w)], amount[:], w)
+		unitsDivMod(prod[:], sum[:], q[:])
+		copy(shares[i][:], q[:aggValueUnits]) // share is no more than amount
+		copy(rems[i][:], prod[:DoubleMaxUnits])
+		unitsSub(left[:], shares[i][:])
+	}
+	// left is less than number of parts with non-zero remainder
+	var n int
+	for i := len(left) - 1; i >= 0; i-- {
+		n = n*Unit + int(left[i])
+	}
+	order := make([]int, 0, len(weights))
+	for i, w := range weights {
+		if unitsNonZero(w) {
+			order = append(order, i)
+		}
+	}
+	if policy == AllocLargestRemainder {
+		sort.SliceStable(order, func(i, j int) bool {
+			return unitsCmp(rems[order[i]][:], rems[order[j]][:]) > 0
+		})
+	}
+	for i := 0; i < n; i++ {
+		unitsMulAdd(shares[order[i%len(order)]][:], 1, 1)
+	}
+	neg := total.IsNeg()
+	parts := make([]FixedDecimal, len(weights))
+	for i := range shares {
+		if err := parts[i].setCoefUnits(shares[i][:], scale, neg); err != nil {
+			return nil, err
+		}
+	}
+	return parts, nil
+}
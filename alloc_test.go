@@ -0,0 +1,113 @@
+package fxd
+
+import "testing"
+
+func TestDecimalAllocate(t *testing.T) {
+	type tcase struct {
+		total    string
+		ratios   []string
+		scale    int
+		policy   AllocPolicy
+		expected []string
+	}
+	for _, c := range []tcase{
+		{"100", []string{"1", "2", "3"}, 2, AllocLargestRemainder, []string{"16.67", "33.33", "50.00"}},
+		{"-100", []string{"1", "2", "3"}, 2, AllocLargestRemainder, []string{"-16.67", "-33.33", "-50.00"}},
+		{"1.00", []string{"1", "3", "3"}, 2, AllocLargestRemainder, []string{"0.14", "0.43", "0.43"}},
+		{"1.00", []string{"1", "3", "3"}, 2, AllocRoundRobin, []string{"0.15", "0.43", "0.42"}},
+		{"-1.00", []string{"1", "3", "3"}, 2, AllocRoundRobin, []string{"-0.15", "-0.43", "-0.42"}},
+		{"0.01", []string{"0", "1", "1"}, 2, AllocLargestRemainder, []string{"0.00", "0.01", "0.00"}},
+		{"0.01", []string{"0", "1", "1"}, 2, AllocRoundRobin, []string{"0.00", "0.01", "0.00"}},
+		{"10", []string{"0.5", "1.5"}, 0, AllocLargestRemainder, []string{"3", "7"}},
+		{"0", []string{"1", "2"}, 2, AllocLargestRemainder, []string{"0.00", "0.00"}},
+		{"0.05", []string{"0.000000000000000000000000000001", "0.000000000000000000000000000002"}, 2, AllocLargestRemainder, []string{"0.02", "0.03"}},
+		{"99999999999999999999999999999999999999999999999999999999999999999", []string{"1", "1", "1", "1"}, 0, AllocLargestRemainder, []string{
+			"25000000000000000000000000000000000000000000000000000000000000000",
+			"25000000000000000000000000000000000000000000000000000000000000000",
+			"25000000000000000000000000000000000000000000000000000000000000000",
+			"24999999999999999999999999999999999999999999999999999999999999999",
+		}},
+	} {
+		total := mustParse(c.total)
+		ratios := make([]FixedDecimal, len(c.ratios))
+		for i, s := range c.ratios {
+			ratios[i] = mustParse(s)
+		}
+		parts, err := Allocate(&total, ratios, c.scale, c.policy)
+		if err != nil {
+			t.Fatalf("allocate %v by %v failed: %v", c.total, c.ratios, err)
+		}
+		for i, s := range c.expected {
+			if parts[i].ToString(-1) != s {
+				t.Fatalf("allocate %v by %v part %v mismatch: actual=%v, expected=%v", c.total, c.ratios, i, parts[i].ToString(-1), s)
+			}
+		}
+	}
+}
+
+func TestDecimalSplit(t *testing.T) {
+	type tcase struct {
+		total    string
+		n        int
+		scale    int
+		expected []string
+	}
+	for _, c := range []tcase{
+		{"100.00", 3, 2, []string{"33.34", "33.33", "33.33"}},
+		{"-100.00", 3, 2, []string{"-33.34", "-33.33", "-33.33"}},
+		{"0.05", 3, 2, []string{"0.02", "0.02", "0.01"}},
+		{"-0.01", 3, 2, []string{"-0.01", "0.00", "0.00"}},
+		{"1", 4, 3, []string{"0.250", "0.250", "0.250", "0.250"}},
+		{"7", 1, 0, []string{"7"}},
+	} {
+		total := mustParse(c.total)
+		for _, policy := range []AllocPolicy{AllocLargestRemainder, AllocRoundRobin} {
+			parts, err := Split(&total, c.n, c.scale, policy)
+			if err != nil {
+				t.Fatalf("split %v failed: %v", c.total, err)
+			}
+			var sum FixedDecimal
+			sum.SetZero()
+			for i, s := range c.expected {
+				if parts[i].ToString(-1) != s {
+					t.Fatalf("split %v part %v mismatch: actual=%v, expected=%v", c.total, i, parts[i].ToString(-1), s)
+				}
+				DecimalAdd(&sum, &parts[i], &sum)
+			}
+			if !sum.Equal(&total) {
+				t.Fatalf("split %v sum mismatch: actual=%v", c.total, sum.ToString(-1))
+			}
+		}
+	}
+}
+
+func TestDecimalAllocateError(t *testing.T) {
+	one := mustParse("1")
+	inexact := mustParse("1.005")
+	nan := parseSpecial("NaN")
+	zeros := parseColumn("0", "0.00")
+	if _, err := Split(&inexact, 2, 2, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := Split(&nan, 2, 2, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := Split(&one, 0, 2, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := Split(&one, 2, MaxFrac+1, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := Allocate(&one, zeros, 2, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := Allocate(&one, parseColumn("1", "-1", "1"), 2, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := Allocate(&one, parseColumn("1", "Inf"), 2, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := Allocate(&one, nil, 2, AllocLargestRemainder); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
@@ -2,6 +2,9 @@
 //
 // Add, Sub, Mul and Div operators are implemented based
 // on Kunth's Algorithm 4.3.1
+//
+// The result of all operators can be the same decimal as
+// any operand, e.g. DecimalAdd(&x, &y, &x).
 package fxd
 
 func DecimalAddAny(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
@@ -179,6 +182,32 @@ func DecimalMod(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) erro
 	return nil
 }
 
+// AddAssign adds rhs to this decimal in place.
+func (fd *FixedDecimal) AddAssign(rhs *FixedDecimal) error {
+	return DecimalAdd(fd, rhs, fd)
+}
+
+// SubAssign subtracts rhs from this decimal in place.
+func (fd *FixedDecimal) SubAssign(rhs *FixedDecimal) error {
+	return DecimalSub(fd, rhs, fd)
+}
+
+// MulAssign multiplies this decimal by rhs in place.
+func (fd *FixedDecimal) MulAssign(rhs *FixedDecimal) error {
+	return DecimalMul(fd, rhs, fd)
+}
+
+// DivAssign divides this decimal by rhs in place.
+// incrFrac has same meaning as DecimalDiv.
+func (fd *FixedDecimal) DivAssign(rhs *FixedDecimal, incrFrac int) error {
+	return DecimalDiv(fd, rhs, fd, incrFrac)
+}
+
+// ModAssign sets this decimal to the remainder of dividing by rhs.
+func (fd *FixedDecimal) ModAssign(rhs *FixedDecimal) error {
+	return DecimalMod(fd, rhs, fd)
+}
+
 // addAbs sums two decimals' absolute values.
 // Separate units into 3 segments, intgSeg, commonSeg, fracSeg
 // lhs:  |  xxxx  |  xxxx.xxxx  |
@@ -190,9 +219,14 @@ func DecimalMod(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) erro
 // commonSeg uses normal addition, taking care of carry.
 // intgSeg is added with 0 and carry.
 func addAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
+	return addAbsUnits(lhs, rhs, result, lhs.IntgUnits(), lhs.FracUnits(), rhs.IntgUnits(), rhs.FracUnits())
+}
+
+// addAbsUnits is addAbs with unit numbers of operands precomputed.
+func addAbsUnits(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal, liu, lfu, riu, rfu int) error {
+	var lbuf, rbuf FixedDecimal
+	lhs, rhs = unaliasOperands(lhs, rhs, result, &lbuf, &rbuf)
 	result.Reset() // always clear result first
-	liu, lfu := lhs.IntgUnits(), lhs.FracUnits()
-	riu, rfu := rhs.IntgUnits(), rhs.FracUnits()
 	var lhsIdx, rhsIdx, resultIdx int
 	fracUnitDiff := lfu - rfu
 	if fracUnitDiff > 0 { // lhs has more fracSeg
@@ -270,9 +304,14 @@ func addAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
 // ---------------------------------------
 //       |intgSeg |  commonSeg  |fracSeg |
 func subAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) (bool, error) {
+	return subAbsUnits(lhs, rhs, result, lhs.IntgUnits(), lhs.FracUnits(), rhs.IntgUnits(), rhs.FracUnits())
+}
+
+// subAbsUnits is subAbs with unit numbers of operands precomputed.
+func subAbsUnits(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal, liu, lfu, riu, rfu int) (bool, error) {
+	var lbuf, rbuf FixedDecimal
+	lhs, rhs = unaliasOperands(lhs, rhs, result, &lbuf, &rbuf)
 	result.Reset() // always clear result first
-	liu, lfu := lhs.IntgUnits(), lhs.FracUnits()
-	riu, rfu := rhs.IntgUnits(), rhs.FracUnits()
 	var lhsIdx, rhsIdx, resultIdx int
 	var borrow int32
 	fracUnitDiff := lfu - rfu
@@ -340,6 +379,13 @@ func subAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) (bool, e
 // The result precision is extended to the maximum possible one, until reaching
 // the limitation of MaxUnits or MaxFracUnits.
 func mulAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
+	return mulAbsUnits(lhs, rhs, result, lhs.IntgUnits(), lhs.FracUnits(), rhs.IntgUnits(), rhs.FracUnits())
+}
+
+// mulAbsUnits is mulAbs with unit numbers of operands precomputed.
+func mulAbsUnits(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal, liu, lfu, riu, rfu int) error {
+	var lbuf, rbuf FixedDecimal
+	lhs, rhs = unaliasOperands(lhs, rhs, result, &lbuf, &rbuf)
 	result.Reset() // always clear result first
 	// result integral digits should be sum of left and right integral digits
 	resultIntgDigits := int(lhs.Intg() + rhs.Intg())
@@ -356,8 +402,6 @@ func mulAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
 	if resultFracUnits > MaxFracUnits { // still exceeds maximum fractional digits
 		resultFracUnits = MaxFracUnits
 	}
-	liu, lfu := lhs.IntgUnits(), lhs.FracUnits()
-	riu, rfu := rhs.IntgUnits(), rhs.FracUnits()
 	// because result fractional part may be truncated, we need to calculate
 	// how many units has to be shifted and can be ignored in calculation.
 	// 1. If leftIdx + rightIdx - shiftUnits < -1, we can ignore the
@@ -395,6 +439,11 @@ func mulAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
 		}
 		carry = 0
 	}
+	// integral units are sum of operands', leading zero units are removed
+	// so that repeated multiplication does not truncate fractional part
+	for resultIntgUnits > 0 && result.lsu[resultFracUnits+resultIntgUnits-1] == 0 {
+		resultIntgUnits--
+	}
 	result.frac = minInt8(int8(resultFracDigits), int8(resultFracUnits)*DigitsPerUnit)
 	result.intg = int8(resultIntgUnits) * DigitsPerUnit
 	return nil
@@ -403,6 +452,8 @@ func mulAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
 // divAbs divides two decimals' absolute values.
 // It's implementation of Knuth's Algorithm 4.3.1 D, with support on frational numbers.
 func divAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal, incrFrac int) error {
+	var lbuf, rbuf FixedDecimal
+	lhs, rhs = unaliasOperands(lhs, rhs, result, &lbuf, &rbuf)
 	result.Reset() // always clear result first
 	lhsIntg := int(lhs.Intg())
 	liu := getUnits(lhsIntg)
@@ -551,13 +602,16 @@ func divAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal, incrFrac
 		v := u0*Unit + u1
 		qhat := v / vd0
 		rhat := v - qhat*vd0
-		// qhat cannot be greater or equal to Unit
-		assertTrue(qhat < Unit, "qhat must be less than Unit")
+		if qhat >= Unit { // u0 equals to vd0, but quotient unit is always less than Unit
+			qhat = Unit - 1
+			rhat = v - qhat*vd0
+		}
 		var u2 int64
 		if i > 0 {
 			u2 = int64(buf1[i-1])
 		}
-		for qhat*vd1 > rhat*Unit+u2 { // check if qhat can satisfy next unit
+		// rhat no less than Unit means qhat already satisfies next unit
+		for rhat < Unit && qhat*vd1 > rhat*Unit+u2 { // check if qhat can satisfy next unit
 			qhat--      // decrese qhat
 			rhat += vd0 // increase rhat
 		}
@@ -578,15 +632,16 @@ func divAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal, incrFrac
 		}
 		borrow = buf1[msIdx] - int32(carry) + borrow
 		if borrow == -1 { // qhat is larger, cannot satisfy the whole decimal
-			// D6. add back (reverse subtract)
-			qhat--                            // decrease qhat
-			borrow = 0                        // reset borrow to zero
-			for i := 0; i < rhsNonZero; i++ { // reverse subtract
-				buf1[i+j], borrow = subWithBorrow(0, buf1[i+j], borrow)
+			// D6. add back divisor, the carry out cancels the borrow
+			qhat-- // decrease qhat
+			var addCarry int32
+			for k, msIdx = 0, i-rhsNonZero; k <= rhsNonZero; k, msIdx = k+1, msIdx+1 {
+				if msIdx >= 0 {
+					buf1[msIdx], addCarry = addWithCarry(buf1[msIdx], buf2[k], addCarry)
+				}
 			}
-		} else {
-			buf1[msIdx] = 0 // clear buf1 because multiply w/ subtract succeeds
 		}
+		buf1[msIdx] = 0             // most significant unit of remainder is always zero
 		result.lsu[j] = int32(qhat) // update result
 	}
 	result.intg = int8(resultIntg)
@@ -595,6 +650,8 @@ func divAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal, incrFrac
 }
 
 func modAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
+	var lbuf, rbuf FixedDecimal
+	lhs, rhs = unaliasOperands(lhs, rhs, result, &lbuf, &rbuf)
 	result.Reset() // always clear result first
 	lhsIntg := int(lhs.Intg())
 	liu := getUnits(lhsIntg) // lhs intg units
@@ -757,12 +814,16 @@ func modAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
 		v := u0*Unit + u1
 		qhat := v / vd0
 		rhat := v - qhat*vd0
-		assertTrue(qhat < Unit, "qhat must be less than Unit")
+		if qhat >= Unit { // u0 equals to vd0, but quotient unit is always less than Unit
+			qhat = Unit - 1
+			rhat = v - qhat*vd0
+		}
 		var u2 int64
 		if i > 0 {
 			u2 = int64(buf1[i-1])
 		}
-		for qhat*vd1 > rhat*Unit+u2 { // check if qhat can satisfy next unit
+		// rhat no less than Unit means qhat already satisfies next unit
+		for rhat < Unit && qhat*vd1 > rhat*Unit+u2 { // check if qhat can satisfy next unit
 			qhat--      // decrese qhat
 			rhat += vd0 // increase rhat
 		}
@@ -779,12 +840,11 @@ func modAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
 		}
 		borrow = buf1[msIdx] - int32(carry) + borrow
 		if borrow == -1 { // qhat is larger, cannot satisfy the whole decimal
-			// D6. add back (reverse subtract)
-			qhat--     // decrease qhat
-			borrow = 0 // reset borrow to zero
-			k = 0
-			for msIdx = i - buf2len + 1; k < buf2len; k, msIdx = k+1, msIdx+1 { // reverse subtract
-				buf1[msIdx], borrow = subWithBorrow(0, buf1[msIdx], borrow)
+			// D6. add back divisor, the carry out cancels the borrow
+			qhat-- // decrease qhat
+			var addCarry int32
+			for k, msIdx = 0, i-buf2len+1; k < buf2len; k, msIdx = k+1, msIdx+1 {
+				buf1[msIdx], addCarry = addWithCarry(buf1[msIdx], buf2[k], addCarry)
 			}
 		}
 		buf1[msIdx] = 0 // clear buf1 because multiply w/ subtract succeeds
@@ -820,6 +880,20 @@ func modAbs(lhs *FixedDecimal, rhs *FixedDecimal, result *FixedDecimal) error {
 	return nil
 }
 
+// unaliasOperands copies operands which share memory with result into
+// given buffers, so result can be cleared before operands are read.
+func unaliasOperands(lhs, rhs, result, lbuf, rbuf *FixedDecimal) (*FixedDecimal, *FixedDecimal) {
+	if lhs == result {
+		*lbuf = *lhs
+		lhs = lbuf
+	}
+	if rhs == result {
+		*rbuf = *rhs
+		rhs = rbuf
+	}
+	return lhs, rhs
+}
+
 // NOTE: carry can only be 0 or 1
 func addWithCarry(a, b, carry int32) (int32, int32) {
 	r := a + b + carry
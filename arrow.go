@@ -0,0 +1,146 @@
+// Apache Arrow Decimal128 and Decimal256
+//
+// Arrow stores decimal as unscaled integer in little-endian two's complement,
+// with 16 bytes for Decimal128 and 32 bytes for Decimal256.
+// Precision and scale are declared in the data type, not in the values.
+package fxd
+
+const (
+	ArrowDecimal128Width   = 16
+	ArrowDecimal256Width   = 32
+	ArrowDecimal128MaxPrec = 38
+	ArrowDecimal256MaxPrec = 76
+)
+
+// AppendArrowDecimal128 appends this decimal as Arrow Decimal128(prec, scale) value
+// to given buffer. Fractional digits beyond scale are rounded.
+// Returns DecErrOverflow if the value does not fit the precision.
+func (fd *FixedDecimal) AppendArrowDecimal128(buf []byte, prec, scale int) ([]byte, error) {
+	return fd.appendArrowDecimal(buf, ArrowDecimal128Width, ArrowDecimal128MaxPrec, prec, scale)
+}
+
+// AppendArrowDecimal256 appends this decimal as Arrow Decimal256(prec, scale) value
+// to given buffer. Fractional digits beyond scale are rounded.
+// Returns DecErrOverflow if the value does not fit the precision.
+func (fd *FixedDecimal) AppendArrowDecimal256(buf []byte, prec, scale int) ([]byte, error) {
+	return fd.appendArrowDecimal(buf, ArrowDecimal256Width, ArrowDecimal256MaxPrec, prec, scale)
+}
+
+func (fd *FixedDecimal) appendArrowDecimal(buf []byte, width, maxPrec, prec, scale int) ([]byte, error) {
+	if prec <= 0 || prec > maxPrec {
+		return buf, DecErrInvalidType
+	}
+	var words [ArrowDecimal256Width / 8]uint64
+	nw := (width + 7) / 8
+	if err := fd.unscaledWords(words[:nw], prec, scale); err != nil {
+		return buf, err
+	}
+	n := len(buf)
+	buf = append(buf, make([]byte, width)...)
+	putWordsLE(buf[n:], words[:nw])
+	return buf, nil
+}
+
+// DecodeArrowDecimal128 creates a new decimal from Arrow Decimal128(prec, scale) value.
+func DecodeArrowDecimal128(bs []byte, prec, scale int) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromArrowDecimal128(bs, prec, scale)
+	return fd, err
+}
+
+// DecodeArrowDecimal256 creates a new decimal from Arrow Decimal256(prec, scale) value.
+func DecodeArrowDecimal256(bs []byte, prec, scale int) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromArrowDecimal256(bs, prec, scale)
+	return fd, err
+}
+
+// FromArrowDecimal128 parses Arrow Decimal128(prec, scale) value and set value to
+// current decimal.
+// Returns DecErrOverflow if the value does not fit the precision, or cannot
+// be held by this decimal.
+func (fd *FixedDecimal) FromArrowDecimal128(bs []byte, prec, scale int) error {
+	return fd.fromArrowDecimal(bs, ArrowDecimal128Width, ArrowDecimal128MaxPrec, prec, scale)
+}
+
+// FromArrowDecimal256 parses Arrow Decimal256(prec, scale) value and set value to
+// current decimal.
+// Returns DecErrOverflow if the value does not fit the precision, or cannot
+// be held by this decimal.
+func (fd *FixedDecimal) FromArrowDecimal256(bs []byte, prec, scale int) error {
+	return fd.fromArrowDecimal(bs, ArrowDecimal256Width, ArrowDecimal256MaxPrec, prec, scale)
+}
+
+func (fd *FixedDecimal) fromArrowDecimal(bs []byte, width, maxPrec, prec, scale int) error {
+	if prec <= 0 || prec > maxPrec {
+		return DecErrInvalidType
+	}
+	if len(bs) != width {
+		return DecErrConversionSyntax
+	}
+	var words [ArrowDecimal256Width / 8]uint64
+	nw := (width + 7) / 8
+	getWordsLE(bs, words[:nw])
+	return fd.fromUnscaledWords(words[:nw], prec, scale)
+}
+
+// AppendArrowDecimal128Array appends all decimals as Arrow Decimal128(prec, scale)
+// values to given buffer.
+// If any value fails, the error is returned with the buffer before the value.
+func AppendArrowDecimal128Array(buf []byte, fds []FixedDecimal, prec, scale int) ([]byte, error) {
+	return appendArrowDecimalArray(buf, fds, ArrowDecimal128Width, ArrowDecimal128MaxPrec, prec, scale)
+}
+
+// AppendArrowDecimal256Array appends all decimals as Arrow Decimal256(prec, scale)
+// values to given buffer.
+// If any value fails, the error is returned with the buffer before the value.
+func AppendArrowDecimal256Array(buf []byte, fds []FixedDecimal, prec, scale int) ([]byte, error) {
+	return appendArrowDecimalArray(buf, fds, ArrowDecimal256Width, ArrowDecimal256MaxPrec, prec, scale)
+}
+
+func appendArrowDecimalArray(buf []byte, fds []FixedDecimal, width, maxPrec, prec, scale int) ([]byte, error) {
+	if cap(buf)-len(buf) < len(fds)*width { // allocate once
+		newBuf := make([]byte, len(buf), len(buf)+len(fds)*width)
+		copy(newBuf, buf)
+		buf = newBuf
+	}
+	var err error
+	for i := range fds {
+		if buf, err = fds[i].appendArrowDecimal(buf, width, maxPrec, prec, scale); err != nil {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
+
+// DecodeArrowDecimal128Array parses raw buffer of Arrow Decimal128(prec, scale)
+// values and appends the decimals to fds.
+func DecodeArrowDecimal128Array(fds []FixedDecimal, bs []byte, prec, scale int) ([]FixedDecimal, error) {
+	return decodeArrowDecimalArray(fds, bs, ArrowDecimal128Width, ArrowDecimal128MaxPrec, prec, scale)
+}
+
+// DecodeArrowDecimal256Array parses raw buffer of Arrow Decimal256(prec, scale)
+// values and appends the decimals to fds.
+func DecodeArrowDecimal256Array(fds []FixedDecimal, bs []byte, prec, scale int) ([]FixedDecimal, error) {
+	return decodeArrowDecimalArray(fds, bs, ArrowDecimal256Width, ArrowDecimal256MaxPrec, prec, scale)
+}
+
+func decodeArrowDecimalArray(fds []FixedDecimal, bs []byte, width, maxPrec, prec, scale int) ([]FixedDecimal, error) {
+	if len(bs)%width != 0 {
+		return fds, DecErrConversionSyntax
+	}
+	n := len(fds)
+	total := n + len(bs)/width
+	if cap(fds) < total {
+		newFds := make([]FixedDecimal, n, total)
+		copy(newFds, fds)
+		fds = newFds
+	}
+	fds = fds[:total]
+	for i := n; i < total; i, bs = i+1, bs[width:] {
+		if err := fds[i].fromArrowDecimal(bs[:width], width, maxPrec, prec, scale); err != nil {
+			return fds[:i], err
+		}
+	}
+	return fds, nil
+}
@@ -0,0 +1,160 @@
+package fxd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecimalArrowDecimal128(t *testing.T) {
+	type tcase struct {
+		input       string
+		prec, scale int
+		expected    []byte
+		output      string
+	}
+	ff := bytes.Repeat([]byte{0xff}, 16)
+	for _, c := range []tcase{
+		{"0", 10, 2, make([]byte, 16), "0.00"},
+		{"1.23", 10, 2, append([]byte{0x7b}, make([]byte, 15)...), "1.23"},
+		{"-1.23", 10, 2, append([]byte{0x85}, ff[:15]...), "-1.23"},
+		{"1.235", 10, 2, append([]byte{0x7c}, make([]byte, 15)...), "1.24"},
+		{"-1.235", 10, 2, append([]byte{0x84}, ff[:15]...), "-1.24"},
+		{"1", 10, 0, append([]byte{0x01}, make([]byte, 15)...), "1"},
+		{"-1", 10, 0, ff, "-1"},
+		{"12300", 10, -2, append([]byte{0x7b}, make([]byte, 15)...), "12300"},
+		{"4294967296", 10, 0, []byte{0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, "4294967296"},
+		{"18446744073709551616", 20, 0, []byte{0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}, "18446744073709551616"},
+		{"-18446744073709551616", 20, 0, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "-18446744073709551616"},
+		// 10^38-1 = 0x4B3B4CA85A86C47A098A223FFFFFFFFF
+		{"9999999999999999999999999999.9999999999", 38, 10, []byte{0xff, 0xff, 0xff, 0xff, 0x3f, 0x22, 0x8a, 0x09, 0x7a, 0xc4, 0x86, 0x5a, 0xa8, 0x4c, 0x3b, 0x4b}, "9999999999999999999999999999.9999999999"},
+	} {
+		fd, err := DecimalFromAsciiString(c.input)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		actual, err := fd.AppendArrowDecimal128(nil, c.prec, c.scale)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if !bytes.Equal(actual, c.expected) {
+			t.Fatalf("encode %v mismatch: actual=%v, expected=%v", c.input, actual, c.expected)
+		}
+		fd2, err := DecodeArrowDecimal128(actual, c.prec, c.scale)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if fd2.ToString(-1) != c.output {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v", c.input, fd2.ToString(-1), c.output)
+		}
+	}
+}
+
+func TestDecimalArrowDecimal256(t *testing.T) {
+	for _, s := range []string{
+		"0",
+		"1",
+		"-1",
+		"99999999999999999999999999999999999999999999999999999999999999999",
+		"-99999999999999999999999999999999999999999999999999999999999999999",
+		"12345678901234567890123456789012345.123456789012345678901234567890",
+		"-0.000000000000000000000000000001",
+	} {
+		fd, err := DecimalFromAsciiString(s)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		scale := int(fd.Frac())
+		buf, err := fd.AppendArrowDecimal256(nil, ArrowDecimal256MaxPrec, scale)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if len(buf) != ArrowDecimal256Width {
+			t.Fatalf("length mismatch %v", len(buf))
+		}
+		fd2, err := DecodeArrowDecimal256(buf, ArrowDecimal256MaxPrec, scale)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if fd2.Compare(&fd) != 0 {
+			t.Fatalf("round trip %v mismatch: actual=%v", s, fd2.ToString(-1))
+		}
+	}
+}
+
+func TestDecimalArrowDecimalError(t *testing.T) {
+	fd, _ := DecimalFromAsciiString("123.45")
+	if _, err := fd.AppendArrowDecimal128(nil, 4, 2); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := fd.AppendArrowDecimal128(nil, 39, 2); err != DecErrInvalidType {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := fd.AppendArrowDecimal256(nil, 77, 2); err != DecErrInvalidType {
+		t.Fatalf("failed %v", err)
+	}
+	nan, _ := DecimalFromAsciiString("NaN")
+	if _, err := nan.AppendArrowDecimal128(nil, 10, 2); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	buf, _ := fd.AppendArrowDecimal128(nil, 5, 2)
+	if _, err := DecodeArrowDecimal128(buf, 4, 2); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := DecodeArrowDecimal128(buf[:15], 5, 2); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := DecodeArrowDecimal128(buf, 5, 31); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	// 10^76-1 exceeds maximum digits of decimal
+	max256 := bytes.Repeat([]byte{0xff}, 32)
+	max256[31] = 0x7f
+	if _, err := DecodeArrowDecimal256(max256, ArrowDecimal256MaxPrec, 0); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecimalArrowDecimalArray(t *testing.T) {
+	var fds []FixedDecimal
+	for _, s := range []string{"1.5", "-2.25", "0", "1000000000.01"} {
+		fd, _ := DecimalFromAsciiString(s)
+		fds = append(fds, fd)
+	}
+	for _, width := range []int{ArrowDecimal128Width, ArrowDecimal256Width} {
+		var buf []byte
+		var err error
+		if width == ArrowDecimal128Width {
+			buf, err = AppendArrowDecimal128Array(nil, fds, 18, 2)
+		} else {
+			buf, err = AppendArrowDecimal256Array(nil, fds, 18, 2)
+		}
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if len(buf) != len(fds)*width {
+			t.Fatalf("length mismatch %v", len(buf))
+		}
+		var res []FixedDecimal
+		if width == ArrowDecimal128Width {
+			res, err = DecodeArrowDecimal128Array(nil, buf, 18, 2)
+		} else {
+			res, err = DecodeArrowDecimal256Array(nil, buf, 18, 2)
+		}
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if len(res) != len(fds) {
+			t.Fatalf("length mismatch %v", len(res))
+		}
+		for i := range fds {
+			if res[i].Compare(&fds[i]) != 0 || res[i].Frac() != 2 {
+				t.Fatalf("value mismatch %v != %v", res[i].ToString(-1), fds[i].ToString(-1))
+			}
+		}
+	}
+	// overflow on the last value
+	buf, err := AppendArrowDecimal128Array(nil, fds, 5, 2)
+	if err != DecErrOverflow || len(buf) != 3*ArrowDecimal128Width {
+		t.Fatalf("failed %v %v", err, len(buf))
+	}
+}
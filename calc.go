@@ -0,0 +1,246 @@
+// Value-returning arithmetic and calculator
+//
+// The methods in this file leave operands untouched and return the
+// result as a new decimal, Must* variants panic on error, so they can be
+// chained, e.g. a.MustAdd(b).MustMul(c).
+// Calculator chains several operations and keeps the first error, e.g.
+//
+//	res, err := Calc().Add(a, b).Mul(c).Round(2).Result()
+package fxd
+
+// Add returns sum of this decimal and rhs.
+func (fd FixedDecimal) Add(rhs FixedDecimal) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalAdd(&fd, &rhs, &res)
+	return res, err
+}
+
+// Sub returns difference of this decimal and rhs.
+func (fd FixedDecimal) Sub(rhs FixedDecimal) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalSub(&fd, &rhs, &res)
+	return res, err
+}
+
+// Mul returns product of this decimal and rhs.
+func (fd FixedDecimal) Mul(rhs FixedDecimal) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalMul(&fd, &rhs, &res)
+	return res, err
+}
+
+// Div returns quotient of this decimal and rhs, with DivIncrFrac more
+// fractional digits.
+func (fd FixedDecimal) Div(rhs FixedDecimal) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalDiv(&fd, &rhs, &res, DivIncrFrac)
+	return res, err
+}
+
+// Mod returns remainder of this decimal divided by rhs.
+func (fd FixedDecimal) Mod(rhs FixedDecimal) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalMod(&fd, &rhs, &res)
+	return res, err
+}
+
+// MustAdd is like Add but panics on error.
+func (fd FixedDecimal) MustAdd(rhs FixedDecimal) FixedDecimal {
+	return mustDecimal(fd.Add(rhs))
+}
+
+// MustSub is like Sub but panics on error.
+func (fd FixedDecimal) MustSub(rhs FixedDecimal) FixedDecimal {
+	return mustDecimal(fd.Sub(rhs))
+}
+
+// MustMul is like Mul but panics on error.
+func (fd FixedDecimal) MustMul(rhs FixedDecimal) FixedDecimal {
+	return mustDecimal(fd.Mul(rhs))
+}
+
+// MustDiv is like Div but panics on error.
+func (fd FixedDecimal) MustDiv(rhs FixedDecimal) FixedDecimal {
+	return mustDecimal(fd.Div(rhs))
+}
+
+// MustMod is like Mod but panics on error.
+func (fd FixedDecimal) MustMod(rhs FixedDecimal) FixedDecimal {
+	return mustDecimal(fd.Mod(rhs))
+}
+
+func mustDecimal(fd FixedDecimal, err error) FixedDecimal {
+	if err != nil {
+		panic(err)
+	}
+	return fd
+}
+
+// Calculator accumulates result of chained operations.
+// Once an operation fails, the error is recorded and all following
+// operations are skipped.
+type Calculator struct {
+	acc      FixedDecimal
+	started  bool
+	incrFrac int
+	err      error
+	status   DecStatus
+}
+
+// Calc creates a new calculator without value.
+// The first operand of the first operation becomes the initial value.
+func Calc() *Calculator {
+	return &Calculator{incrFrac: DivIncrFrac}
+}
+
+// SetDivIncrFrac sets incremental fractional digits of division.
+func (c *Calculator) SetDivIncrFrac(incrFrac int) *Calculator {
+	c.incrFrac = incrFrac
+	return c
+}
+
+// Set sets value of the calculator.
+func (c *Calculator) Set(fd FixedDecimal) *Calculator {
+	if c.err == nil && c.check(&fd) {
+		c.acc = fd
+		c.started = true
+	}
+	return c
+}
+
+// Add adds all operands to current value.
+func (c *Calculator) Add(fds ...FixedDecimal) *Calculator {
+	return c.apply(DecimalAdd, fds)
+}
+
+// Sub subtracts all operands from current value.
+func (c *Calculator) Sub(fds ...FixedDecimal) *Calculator {
+	return c.apply(DecimalSub, fds)
+}
+
+// Mul multiplies current value by all operands.
+func (c *Calculator) Mul(fds ...FixedDecimal) *Calculator {
+	return c.apply(DecimalMul, fds)
+}
+
+// Div divides current value by all operands.
+func (c *Calculator) Div(fds ...FixedDecimal) *Calculator {
+	return c.apply(func(lhs, rhs, result *FixedDecimal) error {
+		return DecimalDiv(lhs, rhs, result, c.incrFrac)
+	}, fds)
+}
+
+// Mod sets current value to remainder divided by all operands in turn.
+func (c *Calculator) Mod(fds ...FixedDecimal) *Calculator {
+	return c.apply(DecimalMod, fds)
+}
+
+// Round rounds current value to given fractional digits.
+// DecStatusRounded is recorded if any digit is removed, and DecStatusInexact
+// is also recorded if any removed digit is non-zero.
+// DecErrInvalidValue is recorded if frac is greater than MaxFrac or less
+// than -MaxDigits.
+func (c *Calculator) Round(frac int) *Calculator {
+	if c.err != nil {
+		return c
+	}
+	if frac > MaxFrac || frac < -MaxDigits {
+		c.fail(DecErrInvalidValue)
+		return c
+	}
+	if !c.started {
+		return c
+	}
+	if frac < int(c.acc.Frac()) {
+		var rounded FixedDecimal
+		c.acc.RoundTo(&rounded, frac)
+		c.status |= DecStatusRounded
+		if rounded.Compare(&c.acc) != 0 {
+			c.status |= DecStatusInexact
+		}
+		c.acc = rounded
+		return c
+	}
+	c.acc.Round(frac)
+	return c
+}
+
+// Fail records err as if an operation failed, e.g. an operand is out of
+// domain of a function built on the calculator.
+// It does nothing if an error is already recorded.
+func (c *Calculator) Fail(err error) *Calculator {
+	if c.err == nil && err != nil {
+		c.fail(err)
+	}
+	return c
+}
+
+// Result returns current value and the first error.
+// On error, the value is the result before the failed operation.
+// Zero is returned if no value is set.
+func (c *Calculator) Result() (FixedDecimal, error) {
+	if !c.started && c.err == nil {
+		var zero FixedDecimal
+		zero.SetZero()
+		return zero, nil
+	}
+	return c.acc, c.err
+}
+
+// MustResult is like Result but panics on error.
+func (c *Calculator) MustResult() FixedDecimal {
+	return mustDecimal(c.Result())
+}
+
+// Err returns the first error.
+func (c *Calculator) Err() error {
+	return c.err
+}
+
+// Status returns all status flags recorded.
+func (c *Calculator) Status() DecStatus {
+	return c.status
+}
+
+func (c *Calculator) apply(op func(lhs, rhs, result *FixedDecimal) error, fds []FixedDecimal) *Calculator {
+	for i := range fds {
+		if c.err != nil || !c.check(&fds[i]) {
+			break
+		}
+		if !c.started {
+			c.acc = fds[i]
+			c.started = true
+			continue
+		}
+		var res FixedDecimal // keep last value on error
+		if err := op(&c.acc, &fds[i], &res); err != nil {
+			c.fail(err)
+			break
+		}
+		c.acc = res
+	}
+	return c
+}
+
+// check records invalid operation if given operand is NaN or Inf.
+func (c *Calculator) check(fd *FixedDecimal) bool {
+	if fd.IsNaN() || fd.IsInf() {
+		c.fail(DecErrInvalidValue)
+		return false
+	}
+	return true
+}
+
+func (c *Calculator) fail(err error) {
+	c.err = err
+	switch err {
+	case DecErrOverflow:
+		c.status |= DecStatusOverflow
+	case DecErrDivisionByZero:
+		c.status |= DecStatusDivisionByZero
+	case DecErrConversionSyntax:
+		c.status |= DecStatusConversionSyntax
+	default:
+		c.status |= DecStatusInvalidOperation
+	}
+}
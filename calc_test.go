@@ -0,0 +1,143 @@
+package fxd
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustParse(s string) FixedDecimal {
+	fd, err := DecimalFromAsciiString(s)
+	if err != nil {
+		panic(err)
+	}
+	return fd
+}
+
+func TestDecimalValueArith(t *testing.T) {
+	a, b := mustParse("1.5"), mustParse("-0.25")
+	type tcase struct {
+		op       func(rhs FixedDecimal) (FixedDecimal, error)
+		must     func(rhs FixedDecimal) FixedDecimal
+		expected string
+	}
+	for i, c := range []tcase{
+		{a.Add, a.MustAdd, "1.25"},
+		{a.Sub, a.MustSub, "1.75"},
+		{a.Mul, a.MustMul, "-0.375"},
+		{a.Div, a.MustDiv, "-6.000000000000000000"},
+		{a.Mod, a.MustMod, "0.00"},
+	} {
+		res, err := c.op(b)
+		if err != nil || res.ToString(-1) != c.expected {
+			t.Fatalf("case %v mismatch: actual=%v, expected=%v, err=%v", i, res.ToString(-1), c.expected, err)
+		}
+		if res = c.must(b); res.ToString(-1) != c.expected {
+			t.Fatalf("case %v mismatch: actual=%v, expected=%v", i, res.ToString(-1), c.expected)
+		}
+	}
+	// operands are unchanged
+	if a.ToString(-1) != "1.5" || b.ToString(-1) != "-0.25" {
+		t.Fatal("failed")
+	}
+	zero := mustParse("0")
+	if _, err := a.Div(zero); err != DecErrDivisionByZero {
+		t.Fatalf("failed %v", err)
+	}
+	defer func() {
+		if r := recover(); r != DecErrDivisionByZero {
+			t.Fatalf("failed %v", r)
+		}
+	}()
+	a.MustDiv(zero)
+}
+
+func TestDecimalValueChain(t *testing.T) {
+	a, b, c := mustParse("1.5"), mustParse("2.25"), mustParse("-3")
+	if res := a.MustAdd(b).MustMul(c).MustSub(a).MustDiv(b); res.ToString(-1) != "-5.666666666666666666" {
+		t.Fatalf("failed %v", res.ToString(-1))
+	}
+	res, err := mustParse("7").MustMod(b).Add(a)
+	if err != nil || res.ToString(-1) != "1.75" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+}
+
+func TestCalculator(t *testing.T) {
+	a, b, c := mustParse("1.5"), mustParse("2.25"), mustParse("3")
+	res, err := Calc().Add(a, b).Mul(c).Sub(a).Result()
+	if err != nil || res.ToString(-1) != "9.75" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	calc := Calc().Set(c).Div(mustParse("7")).Round(2)
+	if res = calc.MustResult(); res.ToString(-1) != "0.43" {
+		t.Fatalf("failed %v", res.ToString(-1))
+	}
+	if calc.Status() != DecStatusRounded|DecStatusInexact {
+		t.Fatalf("status mismatch: actual=%x", calc.Status())
+	}
+	calc = Calc().Set(c).SetDivIncrFrac(1).Div(mustParse("2")).Round(1)
+	if res = calc.MustResult(); res.ToString(-1) != "1.5" || calc.Status() != DecStatusRounded {
+		t.Fatalf("failed %v %x", res.ToString(-1), calc.Status())
+	}
+	calc = Calc().Set(mustParse("1.20")).Round(3)
+	if res = calc.MustResult(); res.ToString(-1) != "1.200" || calc.Status() != DecStatusOk {
+		t.Fatalf("failed %v %x", res.ToString(-1), calc.Status())
+	}
+	// extended fractional units are cleared
+	for _, tc := range []struct {
+		frac     int
+		expected string
+	}{
+		{12, "1.500000000001"},
+		{18, "1.500000000000000001"},
+		{30, "1.500000000000000000000000000001"},
+	} {
+		unit := mustParse("0." + strings.Repeat("0", tc.frac-1) + "1")
+		calc = Calc().Set(a).Round(tc.frac).Add(unit)
+		if res = calc.MustResult(); res.ToString(-1) != tc.expected || calc.Status() != DecStatusOk {
+			t.Fatalf("round %v mismatch: actual=%v, expected=%v", tc.frac, res.ToString(-1), tc.expected)
+		}
+	}
+	// frac out of range
+	for _, frac := range []int{MaxFrac + 1, -MaxDigits - 1} {
+		calc = Calc().Set(a).Round(frac)
+		if res, err = calc.Result(); err != DecErrInvalidValue || res.ToString(-1) != "1.5" || calc.Status() != DecStatusInvalidOperation {
+			t.Fatalf("round %v mismatch: actual=%v, err=%v", frac, res.ToString(-1), err)
+		}
+	}
+	if res, err = Calc().Result(); err != nil || !res.IsZero() {
+		t.Fatal("failed")
+	}
+	// first error is kept and following operations are skipped
+	calc = Calc().Add(a, b).Div(mustParse("0")).Add(c).Mod(mustParse("0"))
+	res, err = calc.Result()
+	if err != DecErrDivisionByZero || calc.Err() != err || res.ToString(-1) != "3.75" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	if calc.Status() != DecStatusDivisionByZero {
+		t.Fatalf("status mismatch: actual=%x", calc.Status())
+	}
+	// error of caller is recorded as first error
+	calc = Calc().Add(a).Fail(DecErrInvalidValue).Add(b).Fail(DecErrOverflow)
+	if res, err = calc.Result(); err != DecErrInvalidValue || res.ToString(-1) != "1.5" || calc.Status() != DecStatusInvalidOperation {
+		t.Fatalf("failed %v %v %x", res.ToString(-1), err, calc.Status())
+	}
+	var nan FixedDecimal
+	nan.SetZero()
+	nan.setNaN()
+	calc = Calc().Add(a).Mul(nan)
+	if calc.Err() != DecErrInvalidValue || calc.Status() != DecStatusInvalidOperation {
+		t.Fatalf("failed %v %x", calc.Err(), calc.Status())
+	}
+	max := mustParse("99999999999999999999999999999999999999999999999999999999999999999")
+	calc = Calc().Mul(max, max)
+	if calc.Err() != DecErrOverflow || calc.Status() != DecStatusOverflow {
+		t.Fatalf("failed %v %x", calc.Err(), calc.Status())
+	}
+	defer func() {
+		if r := recover(); r != DecErrOverflow {
+			t.Fatalf("failed %v", r)
+		}
+	}()
+	calc.MustResult()
+}
@@ -0,0 +1,174 @@
+// MySQL-compatible cast from string to decimal
+//
+// Unlike FromBytesString, which rejects any malformed input,
+// the cast is lenient as MySQL does:
+// surrounding whitespaces are trimmed, the longest numeric prefix
+// is consumed and the rest is ignored with a warning,
+// and the value is rounded and clamped to the target DECIMAL(p,s).
+package fxd
+
+type DecCastWarning uint8
+
+const (
+	DecCastOk         DecCastWarning = iota // no warning
+	DecCastTruncated                        // input is not entirely numeric, "Truncated incorrect DECIMAL value"
+	DecCastOutOfRange                       // value does not fit the target type and is clamped, "Out of range value"
+)
+
+func (w DecCastWarning) String() string {
+	switch w {
+	case DecCastOk:
+		return "ok"
+	case DecCastTruncated:
+		return "truncated"
+	case DecCastOutOfRange:
+		return "out of range"
+	default:
+		return "unknown"
+	}
+}
+
+// maximum exponent to parse, any larger value is definitely out of range.
+const castMaxExp = 1 << 16
+
+// DecimalCastFromString creates a new decimal by casting given bytes string
+// to target decimal type.
+func DecimalCastFromString(bs []byte, typ DecimalType) (FixedDecimal, DecCastWarning) {
+	var fd FixedDecimal
+	warn := fd.CastFromString(bs, typ)
+	return fd, warn
+}
+
+// CastFromString casts given bytes string to target decimal type and set value to
+// current decimal, following semantics of MySQL's CAST(str AS DECIMAL(p,s)).
+// The result always has exactly typ.Frac fractional digits.
+// Non-numeric input results in zero with DecCastTruncated warning, and
+// value exceeding the type range is clamped to its maximum or minimum with
+// DecCastOutOfRange warning.
+func (fd *FixedDecimal) CastFromString(bs []byte, typ DecimalType) DecCastWarning {
+	prec, frac := int(typ.Prec), int(typ.Frac)
+	warn := DecCastOk
+	i := 0
+	for i < len(bs) && isSpace(bs[i]) { // trim leading spaces
+		i++
+	}
+	var neg bool
+	if i < len(bs) && (bs[i] == '-' || bs[i] == '+') {
+		neg = bs[i] == '-'
+		i++
+	}
+	intStart := i
+	for i < len(bs) && isDigit(bs[i]) {
+		i++
+	}
+	intEnd := i
+	fracStart, fracEnd := i, i
+	if i < len(bs) && bs[i] == '.' {
+		i++
+		fracStart = i
+		for i < len(bs) && isDigit(bs[i]) {
+			i++
+		}
+		fracEnd = i
+	}
+	if intEnd == intStart && fracEnd == fracStart { // no digits at all
+		fd.castZero(frac)
+		return DecCastTruncated
+	}
+	exp := 0
+	if i < len(bs) && (bs[i] == 'e' || bs[i] == 'E') { // exponent is consumed only if followed by digits
+		j := i + 1
+		var nege bool
+		if j < len(bs) && (bs[j] == '-' || bs[j] == '+') {
+			nege = bs[j] == '-'
+			j++
+		}
+		if j < len(bs) && isDigit(bs[j]) {
+			for ; j < len(bs) && isDigit(bs[j]); j++ {
+				if exp < castMaxExp {
+					exp = exp*10 + int(bs[j]-'0')
+				}
+			}
+			if nege {
+				exp = -exp
+			}
+			i = j
+		}
+	}
+	for i < len(bs) && isSpace(bs[i]) { // trim trailing spaces
+		i++
+	}
+	if i < len(bs) {
+		warn = DecCastTruncated
+	}
+
+	// collect all significant digits, value = digits * 10^exp
+	var digits []byte
+	if intEnd > intStart {
+		digits = bs[intStart:intEnd]
+	}
+	exp -= fracEnd - fracStart
+	if fracEnd > fracStart {
+		if len(digits) == 0 {
+			digits = bs[fracStart:fracEnd]
+		} else {
+			digits = append(append(make([]byte, 0, len(digits)+fracEnd-fracStart), digits...), bs[fracStart:fracEnd]...)
+		}
+	}
+	for len(digits) > 0 && digits[0] == '0' { // strip leading zeros
+		digits = digits[1:]
+	}
+	if len(digits) == 0 {
+		fd.castZero(frac)
+		return warn
+	}
+	intg := len(digits) + exp // number of integral digits
+	if intg > prec-frac {
+		fd.castClamp(prec, frac, neg)
+		return DecCastOutOfRange
+	}
+	// keep one more fractional digit for rounding
+	keep := intg + frac + 1
+	if keep <= 0 { // even the rounding digit is zero
+		fd.castZero(frac)
+		return warn
+	}
+	if keep < len(digits) {
+		digits = digits[:keep]
+	}
+
+	// build coefficient of digits, value = coef * 10^(-scale), and round it to frac
+	var coef [DoubleMaxUnits]int32
+	for _, c := range digits {
+		unitsMulAdd(coef[:], 10, int64(c-'0'))
+	}
+	if scale := len(digits) - intg; scale > frac {
+		coefDivPow10HalfUp(coef[:], scale-frac)
+	} else {
+		coefMulPow10(coef[:], frac-scale)
+	}
+	// carry of rounding may exceed the range
+	if unitsDigits(coef[:]) > prec || fd.setCoefUnits(coef[:], frac, neg) != nil {
+		fd.castClamp(prec, frac, neg)
+		return DecCastOutOfRange
+	}
+	if fd.allUnitsZero() {
+		fd.castZero(frac)
+	}
+	return warn
+}
+
+// castZero sets zero with given frac.
+func (fd *FixedDecimal) castZero(frac int) {
+	fd.SetZero()
+	fd.frac = int8(frac)
+}
+
+// castClamp sets maximum value of DECIMAL(prec, frac), or minimum value if neg is true.
+func (fd *FixedDecimal) castClamp(prec, frac int, neg bool) {
+	var coef [DoubleMaxUnits]int32
+	for j := 0; j < prec; j++ {
+		unitsMulAdd(coef[:], 10, 9)
+	}
+	fd.setCoefUnits(coef[:], frac, neg) // prec digits always fit
+}
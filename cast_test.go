@@ -0,0 +1,72 @@
+package fxd
+
+import "testing"
+
+func TestDecimalCastFromString(t *testing.T) {
+	type tcase struct {
+		input      string
+		prec, frac int
+		expected   string
+		warn       DecCastWarning
+	}
+	for _, c := range []tcase{
+		{"12.5", 10, 2, "12.50", DecCastOk},
+		{" 7 ", 10, 0, "7", DecCastOk},
+		{"\t-7\n", 10, 0, "-7", DecCastOk},
+		{"12.5abc", 10, 1, "12.5", DecCastTruncated},
+		{"abc", 10, 2, "0.00", DecCastTruncated},
+		{"", 10, 0, "0", DecCastTruncated},
+		{"   ", 10, 0, "0", DecCastTruncated},
+		{".", 10, 0, "0", DecCastTruncated},
+		{"-", 10, 0, "0", DecCastTruncated},
+		{".5", 10, 1, "0.5", DecCastOk},
+		{"5.", 10, 1, "5.0", DecCastOk},
+		{"+5", 10, 0, "5", DecCastOk},
+		{"1.005", 10, 2, "1.01", DecCastOk},
+		{"-1.005", 10, 2, "-1.01", DecCastOk},
+		{"1.004999", 10, 2, "1.00", DecCastOk},
+		{"-0.001", 10, 2, "0.00", DecCastOk},
+		{"1e2", 10, 0, "100", DecCastOk},
+		{"1.5E+2", 10, 0, "150", DecCastOk},
+		{"15e-1", 10, 1, "1.5", DecCastOk},
+		{"1e", 10, 0, "1", DecCastTruncated},
+		{"1e+", 10, 0, "1", DecCastTruncated},
+		{"1ex", 10, 0, "1", DecCastTruncated},
+		{"2e-100", 10, 2, "0.00", DecCastOk},
+		{"000000000000000000000000000000000000000000000000000000000000000000000012", 10, 0, "12", DecCastOk},
+		{"0.000000000000000000000000000000000000000000000000000000000000000000000012", 10, 2, "0.00", DecCastOk},
+		{"1000", 5, 2, "999.99", DecCastOutOfRange},
+		{"-1000", 5, 2, "-999.99", DecCastOutOfRange},
+		{"999.995", 5, 2, "999.99", DecCastOutOfRange},
+		{"999.994", 5, 2, "999.99", DecCastOk},
+		{"-9.995", 3, 2, "-9.99", DecCastOutOfRange},
+		{"-0.005", 3, 2, "-0.01", DecCastOk},
+		{"123e5", 10, 1, "12300000.0", DecCastOk},
+		{"0.0000000000000000000000000000005", 30, 30, "0.000000000000000000000000000001", DecCastOk},
+		{"1e100", 65, 0, "99999999999999999999999999999999999999999999999999999999999999999", DecCastOutOfRange},
+		{"1e100000000000", 10, 0, "9999999999", DecCastOutOfRange},
+		{"1", 2, 2, "0.99", DecCastOutOfRange},
+		{"0.5", 2, 2, "0.50", DecCastOk},
+		{"1xyz", 5, 2, "1.00", DecCastTruncated},
+		{"99999xyz", 4, 0, "9999", DecCastOutOfRange},
+		{"123456789012345678901234567890.123456789012345678901234567890123", 65, 30, "123456789012345678901234567890.123456789012345678901234567890", DecCastOk},
+	} {
+		typ, err := NewDecimalType(c.prec, c.frac)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		fd, warn := DecimalCastFromString([]byte(c.input), typ)
+		actual := fd.ToString(-1)
+		if actual != c.expected || warn != c.warn {
+			t.Fatalf("cast %q as DECIMAL(%v,%v) mismatch: actual=%v(%v), expected=%v(%v)", c.input, c.prec, c.frac, actual, warn, c.expected, c.warn)
+		}
+	}
+}
+
+func TestNewDecimalType(t *testing.T) {
+	for _, c := range [][2]int{{0, 0}, {66, 0}, {10, 31}, {5, 6}, {10, -1}} {
+		if _, err := NewDecimalType(c[0], c[1]); err == nil {
+			t.Fatalf("failed DECIMAL(%v,%v)", c[0], c[1])
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// CBOR decimal fraction
+//
+// RFC 8949 tag 4 represents decimal as array of exponent and mantissa,
+// value = mantissa * 10^exponent:
+//
+//	4([exponent, mantissa])
+//
+// Exponent is an integer, mantissa is an integer or a bignum(tag 2 for
+// positive and tag 3 for negative).
+package fxd
+
+const (
+	cborMajorUint   = 0
+	cborMajorNegint = 1
+	cborMajorBytes  = 2
+	cborMajorArray  = 4
+	cborMajorTag    = 6
+
+	cborTagPosBignum       = 2
+	cborTagNegBignum       = 3
+	cborTagDecimalFraction = 4
+)
+
+// maximum exponent to parse, any larger value definitely overflows.
+const cborMaxExp = 1 << 16
+
+// AppendCBOR appends this decimal as CBOR decimal fraction to given buffer.
+// Exponent is negative of the fractional digit number of this decimal.
+// Returns DecErrInvalidValue if this decimal is NaN or Inf.
+func (fd *FixedDecimal) AppendCBOR(buf []byte) ([]byte, error) {
+	if fd.IsNaN() || fd.IsInf() {
+		return buf, DecErrInvalidValue
+	}
+	var coef [DoubleMaxUnits]int32
+	frac := int(fd.Frac())
+	fd.coefUnits(coef[:], frac)
+	var words [protoMaxWords]uint64
+	unitsToWords(coef[:], words[:]) // MaxDigits always fit
+	neg := fd.IsNeg() && unitsNonZero(coef[:])
+
+	buf = appendCBORHead(buf, cborMajorTag, cborTagDecimalFraction)
+	buf = appendCBORHead(buf, cborMajorArray, 2)
+	if frac > 0 {
+		buf = appendCBORHead(buf, cborMajorNegint, uint64(frac-1))
+	} else {
+		buf = appendCBORHead(buf, cborMajorUint, 0)
+	}
+	if neg { // negative integer n is encoded as -1-n
+		wordsSubOne(words[:])
+	}
+	if words[1]|words[2]|words[3] == 0 {
+		if neg {
+			return appendCBORHead(buf, cborMajorNegint, words[0]), nil
+		}
+		return appendCBORHead(buf, cborMajorUint, words[0]), nil
+	}
+	if neg {
+		buf = appendCBORHead(buf, cborMajorTag, cborTagNegBignum)
+	} else {
+		buf = appendCBORHead(buf, cborMajorTag, cborTagPosBignum)
+	}
+	// magnitude in big-endian without leading zeros
+	n := len(words) * 8
+	for byte(words[(n-1)/8]>>(uint((n-1)%8)*8)) == 0 {
+		n--
+	}
+	buf = appendCBORHead(buf, cborMajorBytes, uint64(n))
+	for j := n - 1; j >= 0; j-- {
+		buf = append(buf, byte(words[j/8]>>(uint(j%8)*8)))
+	}
+	return buf, nil
+}
+
+func appendCBORHead(buf []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(buf, major|byte(arg))
+	case arg <= 0xff:
+		return append(buf, major|24, byte(arg))
+	case arg <= 0xffff:
+		return append(buf, major|25, byte(arg>>8), byte(arg))
+	case arg <= 0xffffffff:
+		return append(buf, major|26, byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	default:
+		return append(buf, major|27, byte(arg>>56), byte(arg>>48), byte(arg>>40), byte(arg>>32),
+			byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	}
+}
+
+// readCBORHead reads the initial byte and argument of a data item.
+// Indefinite length is not supported.
+func readCBORHead(bs []byte) (major byte, arg uint64, rest []byte, ok bool) {
+	if len(bs) == 0 {
+		return 0, 0, nil, false
+	}
+	major, ai := bs[0]>>5, bs[0]&0x1f
+	bs = bs[1:]
+	if ai < 24 {
+		return major, uint64(ai), bs, true
+	}
+	if ai > 27 {
+		return 0, 0, nil, false
+	}
+	n := 1 << (ai - 24)
+	if len(bs) < n {
+		return 0, 0, nil, false
+	}
+	for _, b := range bs[:n] {
+		arg = arg<<8 | uint64(b)
+	}
+	return major, arg, bs[n:], true
+}
+
+// DecodeCBOR creates a new decimal from CBOR decimal fraction.
+func DecodeCBOR(bs []byte) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromCBOR(bs)
+	return fd, err
+}
+
+// FromCBOR parses CBOR decimal fraction and set value to current decimal.
+// The input must contain exactly one data item.
+// Returns DecErrOverflow if the value cannot be held by this decimal.
+func (fd *FixedDecimal) FromCBOR(bs []byte) error {
+	major, arg, bs, ok := readCBORHead(bs)
+	if !ok || major != cborMajorTag || arg != cborTagDecimalFraction {
+		return DecErrConversionSyntax
+	}
+	if major, arg, bs, ok = readCBORHead(bs); !ok || major != cborMajorArray || arg != 2 {
+		return DecErrConversionSyntax
+	}
+	if major, arg, bs, ok = readCBORHead(bs); !ok || (major != cborMajorUint && major != cborMajorNegint) {
+		return DecErrConversionSyntax
+	}
+	if arg >= cborMaxExp {
+		return DecErrOverflow
+	}
+	exp := int(arg)
+	if major == cborMajorNegint {
+		exp = -1 - exp
+	}
+	var words [protoMaxWords]uint64
+	var neg bool
+	if major, arg, bs, ok = readCBORHead(bs); !ok {
+		return DecErrConversionSyntax
+	}
+	switch major {
+	case cborMajorUint, cborMajorNegint:
+		words[0] = arg
+		neg = major == cborMajorNegint
+	case cborMajorTag:
+		if arg != cborTagPosBignum && arg != cborTagNegBignum {
+			return DecErrConversionSyntax
+		}
+		neg = arg == cborTagNegBignum
+		if major, arg, bs, ok = readCBORHead(bs); !ok || major != cborMajorBytes || arg > uint64(len(bs)) {
+			return DecErrConversionSyntax
+		}
+		data := bs[:arg]
+		bs = bs[arg:]
+		for len(data) > 0 && data[0] == 0 {
+			data = data[1:]
+		}
+		if len(data) > len(words)*8 {
+			return DecErrOverflow
+		}
+		for i, j := len(data)-1, 0; i >= 0; i, j = i-1, j+1 {
+			words[j/8] |= uint64(data[i]) << (uint(j%8) * 8)
+		}
+	default:
+		return DecErrConversionSyntax
+	}
+	if len(bs) != 0 {
+		return DecErrConversionSyntax
+	}
+	if neg && !wordsAddOne(words[:]) {
+		return DecErrOverflow
+	}
+	var coef [DoubleMaxUnits]int32
+	if !wordsToUnits(words[:], coef[:]) {
+		return DecErrOverflow
+	}
+	return fd.setCoefUnits(coef[:], trimCoefScale(coef[:], -exp), neg)
+}
+
+// wordsSubOne subtracts one from non-zero little-endian words.
+func wordsSubOne(words []uint64) {
+	for i := range words {
+		words[i]--
+		if words[i] != ^uint64(0) {
+			return
+		}
+	}
+}
+
+// wordsAddOne adds one to little-endian words.
+// Returns false on overflow.
+func wordsAddOne(words []uint64) bool {
+	for i := range words {
+		words[i]++
+		if words[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package fxd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecimalCBOR(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected string
+	}
+	for _, c := range []tcase{
+		// example of RFC 8949
+		{"273.15", "c48221196ab3"},
+		{"0", "c4820000"},
+		{"0.00", "c4822100"},
+		{"1", "c4820001"},
+		{"-1", "c4820020"},
+		{"-1.5", "c482202e"},
+		{"0.000000000000000000000000000001", "c482381d01"},
+		{"18446744073709551615", "c482001bffffffffffffffff"},
+		{"-18446744073709551616", "c482003bffffffffffffffff"},
+		{"18446744073709551616", "c48200c249010000000000000000"},
+		{"-18446744073709551617", "c48200c349010000000000000000"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		actual, err := fd.AppendCBOR(nil)
+		if err != nil || hex.EncodeToString(actual) != c.expected {
+			t.Fatalf("encode %v mismatch: actual=%x, expected=%v, err=%v", c.input, actual, c.expected, err)
+		}
+		fd2, err := DecodeCBOR(actual)
+		if err != nil || fd2.Compare(&fd) != 0 || fd2.Frac() != fd.Frac() {
+			t.Fatalf("decode %v mismatch: actual=%v, err=%v", c.input, fd2.ToString(-1), err)
+		}
+	}
+	fd, _ := DecimalFromAsciiString("-99999999999999999999999999999999999.999999999999999999999999999999")
+	buf, _ := fd.AppendCBOR(nil)
+	if fd2, err := DecodeCBOR(buf); err != nil || fd2.Compare(&fd) != 0 {
+		t.Fatalf("failed %v %v", fd2.ToString(-1), err)
+	}
+	var nan FixedDecimal
+	nan.SetZero()
+	nan.setNaN()
+	if _, err := nan.AppendCBOR(nil); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	type tcase struct {
+		input  string
+		output string
+		err    error
+	}
+	for _, c := range []tcase{
+		// positive exponent
+		{"c4820205", "500", nil},
+		// non-preferred serialization
+		{"d80482181e1801", "1000000000000000000000000000000", nil},
+		// indefinite length
+		{"d8049f", "", DecErrConversionSyntax},
+		// bignum with leading zeros
+		{"c48200c24300007b", "123", nil},
+		{"c48221c34100", "-0.01", nil},
+		{"", "", DecErrConversionSyntax},
+		{"c5820001", "", DecErrConversionSyntax},
+		{"c4830001", "", DecErrConversionSyntax},
+		{"c48240", "", DecErrConversionSyntax},
+		{"c48200c44100", "", DecErrConversionSyntax},
+		{"c48200c24201", "", DecErrConversionSyntax},
+		{"c482000100", "", DecErrConversionSyntax},
+		// exponent -31
+		{"c482381e01", "", DecErrOverflow},
+		// exponent -31 and -32 with trailing zeros
+		{"c482381e0a", "0.000000000000000000000000000001", nil},
+		{"c482381f3863", "-0.000000000000000000000000000001", nil},
+		{"c482382700", "0.000000000000000000000000000000", nil},
+		// exponent 65
+		{"c482184101", "", DecErrOverflow},
+		{"c4821b000000010000000001", "", DecErrOverflow},
+		{"c48200c25821010000000000000000000000000000000000000000000000000000000000000000", "", DecErrOverflow},
+	} {
+		bs, _ := hex.DecodeString(c.input)
+		fd, err := DecodeCBOR(bs)
+		if err != c.err {
+			t.Fatalf("decode %v mismatch: err=%v, expected=%v", c.input, err, c.err)
+		}
+		if err == nil && fd.ToString(-1) != c.output {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v", c.input, fd.ToString(-1), c.output)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// ClickHouse Decimal in native format
+//
+// ClickHouse stores Decimal(P, S) as unscaled integer in little-endian
+// two's complement, the width depends on precision:
+//
+//	Decimal32(S):  P from 1 to 9, 4 bytes.
+//	Decimal64(S):  P from 10 to 18, 8 bytes.
+//	Decimal128(S): P from 19 to 38, 16 bytes.
+//	Decimal256(S): P from 39 to 76, 32 bytes.
+//
+// A column is stored as consecutive values without any header.
+package fxd
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	ClickHouseDecimalMaxPrec = 76
+
+	// precision of Decimal(P) without scale, and Decimal without arguments
+	clickHouseDefaultPrec = 10
+)
+
+// ClickHouseDecimal is ClickHouse Decimal(Prec, Scale) type.
+type ClickHouseDecimal struct {
+	Prec  int
+	Scale int
+}
+
+// NewClickHouseDecimal creates ClickHouse Decimal(prec, scale) type.
+func NewClickHouseDecimal(prec, scale int) (ClickHouseDecimal, error) {
+	if prec <= 0 || prec > ClickHouseDecimalMaxPrec || scale < 0 || scale > prec {
+		return ClickHouseDecimal{}, DecErrInvalidType
+	}
+	return ClickHouseDecimal{Prec: prec, Scale: scale}, nil
+}
+
+// ParseClickHouseDecimal parses ClickHouse type spec, e.g. "Decimal(18,4)",
+// "Decimal(9)", "Decimal64(4)". Type name is case-insensitive.
+func ParseClickHouseDecimal(spec string) (ClickHouseDecimal, error) {
+	spec = strings.TrimSpace(spec)
+	lparen := strings.IndexByte(spec, '(')
+	if lparen < 0 {
+		if strings.EqualFold(spec, "Decimal") {
+			return NewClickHouseDecimal(clickHouseDefaultPrec, 0)
+		}
+		return ClickHouseDecimal{}, DecErrInvalidType
+	}
+	if spec[len(spec)-1] != ')' {
+		return ClickHouseDecimal{}, DecErrInvalidType
+	}
+	name := strings.TrimSpace(spec[:lparen])
+	args := strings.Split(spec[lparen+1:len(spec)-1], ",")
+	nums := make([]int, len(args))
+	for i, arg := range args {
+		n, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil {
+			return ClickHouseDecimal{}, DecErrInvalidType
+		}
+		nums[i] = n
+	}
+	var prec int
+	switch strings.ToLower(name) {
+	case "decimal":
+		switch len(nums) {
+		case 1:
+			return NewClickHouseDecimal(nums[0], 0)
+		case 2:
+			return NewClickHouseDecimal(nums[0], nums[1])
+		}
+		return ClickHouseDecimal{}, DecErrInvalidType
+	case "decimal32":
+		prec = 9
+	case "decimal64":
+		prec = 18
+	case "decimal128":
+		prec = 38
+	case "decimal256":
+		prec = 76
+	default:
+		return ClickHouseDecimal{}, DecErrInvalidType
+	}
+	if len(nums) != 1 {
+		return ClickHouseDecimal{}, DecErrInvalidType
+	}
+	return NewClickHouseDecimal(prec, nums[0])
+}
+
+// String returns type spec as "Decimal(P, S)".
+func (t ClickHouseDecimal) String() string {
+	return "Decimal(" + strconv.Itoa(t.Prec) + ", " + strconv.Itoa(t.Scale) + ")"
+}
+
+// Width returns byte width of single value.
+func (t ClickHouseDecimal) Width() int {
+	switch {
+	case t.Prec <= 9:
+		return 4
+	case t.Prec <= 18:
+		return 8
+	case t.Prec <= 38:
+		return 16
+	default:
+		return 32
+	}
+}
+
+// AppendValue appends given decimal as value of this type to buffer.
+// Fractional digits beyond scale are rounded.
+// Returns DecErrOverflow if the value does not fit the precision.
+func (t ClickHouseDecimal) AppendValue(buf []byte, fd *FixedDecimal) ([]byte, error) {
+	return fd.appendArrowDecimal(buf, t.Width(), ClickHouseDecimalMaxPrec, t.Prec, t.Scale)
+}
+
+// DecodeValue creates a new decimal from value of this type.
+func (t ClickHouseDecimal) DecodeValue(bs []byte) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.fromArrowDecimal(bs, t.Width(), ClickHouseDecimalMaxPrec, t.Prec, t.Scale)
+	return fd, err
+}
+
+// AppendColumn appends all decimals as column of this type to buffer.
+// If any value fails, the error is returned with the buffer before the value.
+func (t ClickHouseDecimal) AppendColumn(buf []byte, fds []FixedDecimal) ([]byte, error) {
+	return appendArrowDecimalArray(buf, fds, t.Width(), ClickHouseDecimalMaxPrec, t.Prec, t.Scale)
+}
+
+// DecodeColumn parses column of this type and appends the decimals to fds.
+// If any value fails, the error is returned with decimals before the value.
+func (t ClickHouseDecimal) DecodeColumn(fds []FixedDecimal, bs []byte) ([]FixedDecimal, error) {
+	return decodeArrowDecimalArray(fds, bs, t.Width(), ClickHouseDecimalMaxPrec, t.Prec, t.Scale)
+}
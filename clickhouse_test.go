@@ -0,0 +1,108 @@
+package fxd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestParseClickHouseDecimal(t *testing.T) {
+	type tcase struct {
+		spec     string
+		expected ClickHouseDecimal
+		width    int
+	}
+	for _, c := range []tcase{
+		{"Decimal(18,4)", ClickHouseDecimal{18, 4}, 8},
+		{" Decimal( 9 , 2 ) ", ClickHouseDecimal{9, 2}, 4},
+		{"DECIMAL(20)", ClickHouseDecimal{20, 0}, 16},
+		{"Decimal", ClickHouseDecimal{10, 0}, 8},
+		{"Decimal32(3)", ClickHouseDecimal{9, 3}, 4},
+		{"Decimal64(4)", ClickHouseDecimal{18, 4}, 8},
+		{"Decimal128(10)", ClickHouseDecimal{38, 10}, 16},
+		{"Decimal256(20)", ClickHouseDecimal{76, 20}, 32},
+		{"Decimal(39,0)", ClickHouseDecimal{39, 0}, 32},
+	} {
+		typ, err := ParseClickHouseDecimal(c.spec)
+		if err != nil || typ != c.expected {
+			t.Fatalf("parse %v mismatch: actual=%v, expected=%v, err=%v", c.spec, typ, c.expected, err)
+		}
+		if typ.Width() != c.width {
+			t.Fatalf("width %v mismatch: actual=%v, expected=%v", c.spec, typ.Width(), c.width)
+		}
+	}
+	for _, spec := range []string{
+		"", "Decimal(", "Decimal()", "Decimal(0)", "Decimal(77)", "Decimal(5,6)", "Decimal(5,-1)",
+		"Decimal(1,2,3)", "Decimal64(19)", "Decimal64(1,2)", "Decimal64", "Float64", "Decimal(a,b)",
+	} {
+		if _, err := ParseClickHouseDecimal(spec); err != DecErrInvalidType {
+			t.Fatalf("parse %v mismatch: err=%v", spec, err)
+		}
+	}
+	if s := (ClickHouseDecimal{18, 4}).String(); s != "Decimal(18, 4)" {
+		t.Fatalf("string mismatch: actual=%v", s)
+	}
+}
+
+func TestClickHouseDecimalColumn(t *testing.T) {
+	type tcase struct {
+		spec     string
+		inputs   []string
+		expected string
+		outputs  []string
+	}
+	for _, c := range []tcase{
+		{"Decimal32(2)", []string{"1.23", "-1.235", "0", "9999999.99"},
+			"7b00000084ffffff00000000ffc99a3b", []string{"1.23", "-1.24", "0.00", "9999999.99"}},
+		{"Decimal64(4)", []string{"-1", "12345678901234.5678"},
+			"f0d8ffffffffffff4ef330a64b9bb601", []string{"-1.0000", "12345678901234.5678"}},
+		{"Decimal128(0)", []string{"-18446744073709551616"},
+			"0000000000000000ffffffffffffffff", []string{"-18446744073709551616"}},
+	} {
+		typ, _ := ParseClickHouseDecimal(c.spec)
+		fds := make([]FixedDecimal, len(c.inputs))
+		for i, s := range c.inputs {
+			fds[i], _ = DecimalFromAsciiString(s)
+		}
+		buf, err := typ.AppendColumn(nil, fds)
+		if err != nil || hex.EncodeToString(buf) != c.expected {
+			t.Fatalf("encode %v mismatch: actual=%x, expected=%v, err=%v", c.spec, buf, c.expected, err)
+		}
+		actual, err := typ.DecodeColumn(nil, buf)
+		if err != nil || len(actual) != len(c.outputs) {
+			t.Fatalf("decode %v failed: err=%v", c.spec, err)
+		}
+		for i := range actual {
+			if actual[i].ToString(-1) != c.outputs[i] {
+				t.Fatalf("decode %v mismatch: actual=%v, expected=%v", c.spec, actual[i].ToString(-1), c.outputs[i])
+			}
+		}
+	}
+}
+
+func TestClickHouseDecimalError(t *testing.T) {
+	typ, _ := ParseClickHouseDecimal("Decimal(5,2)")
+	fds := make([]FixedDecimal, 3)
+	for i, s := range []string{"1.5", "999.99", "1000"} {
+		fds[i], _ = DecimalFromAsciiString(s)
+	}
+	buf, err := typ.AppendColumn(nil, fds)
+	if err != DecErrOverflow || len(buf) != 8 {
+		t.Fatalf("failed %v %v", len(buf), err)
+	}
+	// 100000 exceeds precision 5 while fits Decimal32
+	buf = append(buf, 0xa0, 0x86, 0x01, 0x00)
+	res, err := typ.DecodeColumn(nil, buf)
+	if err != DecErrOverflow || len(res) != 2 {
+		t.Fatalf("failed %v %v", len(res), err)
+	}
+	if _, err := typ.DecodeColumn(nil, buf[:5]); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+	fd, err := typ.DecodeValue(buf[4:8])
+	if err != nil || fd.ToString(-1) != "999.99" {
+		t.Fatalf("failed %v %v", fd.ToString(-1), err)
+	}
+	if v, err := typ.AppendValue(nil, &fd); err != nil || hex.EncodeToString(v) != "9f860100" {
+		t.Fatalf("failed %x %v", v, err)
+	}
+}
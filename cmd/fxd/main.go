@@ -0,0 +1,262 @@
+// Command fxd evaluates decimal expressions with the fxd library, to
+// reproduce what the library computes for given input.
+//
+// Usage:
+//
+//	fxd [flags] [expression]
+//
+// With an expression, it's evaluated once and the result is printed,
+// an expression starting with minus sign must follow "--".
+// Otherwise expressions are read line by line from standard input,
+// and a line of "name = expression" assigns a variable which can be
+// used by following lines. See package expr for syntax of expressions.
+//
+// Flags:
+//
+//	-incr n         incremental fractional digits of division (default fxd.DivIncrFrac)
+//	-scale n        fractional digits of output, -1 keeps scale of result (default -1)
+//	-round mode     rounding mode of output scale, only half_up is supported
+//	-notation name  plain or sci, e.g. 1234.5 or 1.2345E+3
+//	-var name=value initial variable, can be repeated
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jiangzhe/fxd"
+	"github.com/jiangzhe/fxd/expr"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// roundModes maps names of flag to rounding modes.
+var roundModes = map[string]fxd.DecRoundMode{
+	"half_up": fxd.DecRoundHalfUp,
+}
+
+// session keeps options and variables of evaluation.
+type session struct {
+	incrFrac int
+	scale    int
+	mode     fxd.DecRoundMode
+	sci      bool
+	vars     map[string]fxd.FixedDecimal
+}
+
+// varFlag collects -var flags.
+type varFlag map[string]fxd.FixedDecimal
+
+func (v varFlag) String() string {
+	return ""
+}
+
+func (v varFlag) Set(s string) error {
+	i := strings.IndexByte(s, '=')
+	if i <= 0 {
+		return errors.New("expect name=value")
+	}
+	fd, err := fxd.DecimalFromAsciiString(strings.TrimSpace(s[i+1:]))
+	if err != nil {
+		return err
+	}
+	v[strings.TrimSpace(s[:i])] = fd
+	return nil
+}
+
+// run executes command with arguments, and returns exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	s := &session{vars: make(map[string]fxd.FixedDecimal)}
+	fs := flag.NewFlagSet("fxd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.IntVar(&s.incrFrac, "incr", fxd.DivIncrFrac, "incremental fractional digits of division")
+	fs.IntVar(&s.scale, "scale", -1, "fractional digits of output, -1 keeps scale of result")
+	round := fs.String("round", "half_up", "rounding mode of output scale, only half_up is supported")
+	notation := fs.String("notation", "plain", "notation of output, plain or sci")
+	fs.Var(varFlag(s.vars), "var", "initial variable as name=value, can be repeated")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	mode, ok := roundModes[*round]
+	if !ok {
+		io.WriteString(stderr, "unsupported rounding mode: "+*round+"\n")
+		return 2
+	}
+	s.mode = mode
+	switch *notation {
+	case "plain":
+	case "sci":
+		s.sci = true
+	default:
+		io.WriteString(stderr, "unsupported notation: "+*notation+"\n")
+		return 2
+	}
+	if s.scale > fxd.MaxFrac || s.scale < -1 {
+		io.WriteString(stderr, "scale out of range: "+strconv.Itoa(s.scale)+"\n")
+		return 2
+	}
+	if s.incrFrac > fxd.MaxFrac || s.incrFrac < 0 {
+		io.WriteString(stderr, "incr out of range: "+strconv.Itoa(s.incrFrac)+"\n")
+		return 2
+	}
+	if fs.NArg() > 0 {
+		res, err := s.eval(strings.Join(fs.Args(), " "))
+		if err != nil {
+			io.WriteString(stderr, err.Error()+"\n")
+			return 1
+		}
+		io.WriteString(stdout, res+"\n")
+		return 0
+	}
+	code := 0
+	sc := bufio.NewScanner(stdin)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		res, err := s.exec(line)
+		if err != nil {
+			writeError(stderr, line, err)
+			code = 1
+			continue
+		}
+		io.WriteString(stdout, res+"\n")
+	}
+	if err := sc.Err(); err != nil {
+		io.WriteString(stderr, err.Error()+"\n")
+		return 1
+	}
+	return code
+}
+
+// exec executes a line of REPL, which is an expression or assignment.
+func (s *session) exec(line string) (string, error) {
+	name, src := splitAssign(line)
+	if name == "" {
+		return s.eval(line)
+	}
+	fd, err := s.compute(src)
+	if err != nil {
+		var e *expr.Error
+		if errors.As(err, &e) { // position in whole line
+			err = &expr.Error{Pos: e.Pos + len(line) - len(src), Err: e.Err}
+		}
+		return "", err
+	}
+	s.vars[name] = fd
+	return name + " = " + s.format(fd), nil
+}
+
+// splitAssign returns name and expression of an assignment, or empty
+// name if line is not an assignment.
+func splitAssign(line string) (string, string) {
+	i := strings.IndexByte(line, '=')
+	if i < 0 {
+		return "", line
+	}
+	name := strings.TrimSpace(line[:i])
+	if name == "" {
+		return "", line
+	}
+	for j := 0; j < len(name); j++ {
+		c := name[j]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || j > 0 && c >= '0' && c <= '9') {
+			return "", line
+		}
+	}
+	return name, line[i+1:]
+}
+
+// eval evaluates expression and formats the result.
+func (s *session) eval(src string) (string, error) {
+	fd, err := s.compute(src)
+	if err != nil {
+		return "", err
+	}
+	return s.format(fd), nil
+}
+
+// compute evaluates expression with variables of session.
+func (s *session) compute(src string) (fxd.FixedDecimal, error) {
+	e, err := expr.Parse(src)
+	if err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	prog, err := e.Compile(s.incrFrac)
+	if err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	fd, err := prog.Eval(s.vars)
+	if err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	return fd, nil
+}
+
+// format rounds the result to output scale and converts it to string.
+// Variables keep unrounded values, only the output is rounded.
+func (s *session) format(fd fxd.FixedDecimal) string {
+	if s.scale >= 0 {
+		// s.mode is always fxd.DecRoundHalfUp, the only mode of Round
+		fd.Round(s.scale)
+		if fd.Sign() == 0 { // no negative zero
+			fd.Abs()
+		}
+	}
+	if s.sci {
+		return sciString(fd.ToString(-1))
+	}
+	return fd.ToString(-1)
+}
+
+// sciString converts plain decimal string to scientific notation,
+// all digits after the first significant digit are kept, and zero
+// keeps digits of its scale.
+func sciString(plain string) string {
+	sign := ""
+	if plain[0] == '-' {
+		sign, plain = "-", plain[1:]
+	}
+	intg, frac := plain, ""
+	if i := strings.IndexByte(plain, '.'); i >= 0 {
+		intg, frac = plain[:i], plain[i+1:]
+	}
+	digits := intg + frac
+	exp := len(intg) - 1
+	for len(digits) > 1 && digits[0] == '0' {
+		digits = digits[1:]
+		exp--
+	}
+	if digits == "0" { // zero keeps its fractional digits as scale
+		if frac != "" {
+			return sign + "0." + frac + "E+0"
+		}
+		return sign + "0E+0"
+	}
+	mantissa := digits[:1]
+	if len(digits) > 1 {
+		mantissa += "." + digits[1:]
+	}
+	expSign := "+"
+	if exp < 0 {
+		expSign, exp = "-", -exp
+	}
+	return sign + mantissa + "E" + expSign + strconv.Itoa(exp)
+}
+
+// writeError writes error with a caret under its position.
+func writeError(w io.Writer, line string, err error) {
+	var e *expr.Error
+	if errors.As(err, &e) {
+		io.WriteString(w, line+"\n"+strings.Repeat(" ", e.Pos)+"^\n")
+	}
+	io.WriteString(w, err.Error()+"\n")
+}
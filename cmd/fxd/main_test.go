@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	type tcase struct {
+		args     []string
+		stdin    string
+		code     int
+		expected string
+	}
+	for _, tc := range []tcase{
+		{[]string{"1/3"}, "", 0, "0.333333333\n"},
+		{[]string{"-incr", "10", "1/3"}, "", 0, "0.333333333333333333\n"},
+		{[]string{"-scale", "2", "round(price", "*", "1.0825,", "3)"}, "", 1, ""},
+		{[]string{"-scale", "2", "-var", "price=19.99", "price * 1.0825"}, "", 0, "21.64\n"},
+		{[]string{"-scale", "4", "2.5"}, "", 0, "2.5000\n"},
+		{[]string{"-scale", "0", "--", "-0.4"}, "", 0, "0\n"},
+		{[]string{"-notation", "sci", "1234.5"}, "", 0, "1.2345E+3\n"},
+		{[]string{"-notation", "sci", "--", "-0.00120"}, "", 0, "-1.20E-3\n"},
+		{[]string{"-notation", "sci", "0"}, "", 0, "0E+0\n"},
+		{[]string{"-notation", "sci", "7"}, "", 0, "7E+0\n"},
+		{[]string{"-scale", "2", "-notation", "sci", "--", "-0.001"}, "", 0, "0.00E+0\n"},
+		{[]string{"-notation", "sci", "0.000"}, "", 0, "0.000E+0\n"},
+		{[]string{"1/0"}, "", 1, ""},
+		{[]string{"-round", "half_even", "1"}, "", 2, ""},
+		{[]string{"-notation", "eng", "1"}, "", 2, ""},
+		{[]string{"-scale", "31", "1"}, "", 2, ""},
+		{[]string{"-incr", "-1", "1"}, "", 2, ""},
+		{nil, "price = 19.99\ntax = 8.25\n\nround(price * (1 + tax/100), 2)\n", 0, "price = 19.99\ntax = 8.25\n21.64\n"},
+		{nil, "x = 1\nx / 0\nx + 1\n", 1, "x = 1\n2\n"},
+		// variables are not rounded to output scale
+		{[]string{"-scale", "2"}, "x = 1/3\nx*3\n", 0, "x = 0.33\n1.00\n"},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+		if code != tc.code || stdout.String() != tc.expected {
+			t.Fatalf("%v mismatch: actual=%v,%q, expected=%v,%q, stderr=%v", tc.args, code, stdout.String(), tc.code, tc.expected, stderr.String())
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	run(nil, strings.NewReader("y = 1 + x\n"), &stdout, &stderr)
+	expected := "y = 1 + x\n        ^\npos 8: decimal invalid value\n"
+	if stderr.String() != expected {
+		t.Fatalf("error mismatch: actual=%q, expected=%q", stderr.String(), expected)
+	}
+}
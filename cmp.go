@@ -1,5 +1,8 @@
 package fxd
 
+// Compare compares two normal decimals, returns -1 if this decimal is less
+// than rhs, 0 if equal, and 1 if greater.
+// NaN and Inf flags are not considered, use CompareAny or CompareTotal instead.
 func (fd *FixedDecimal) Compare(rhs *FixedDecimal) int {
 	lneg := fd.IsNeg()
 	rneg := rhs.IsNeg()
@@ -17,6 +20,139 @@ func (fd *FixedDecimal) Compare(rhs *FixedDecimal) int {
 	return cmpAbs(fd, rhs)
 }
 
+// CompareAny compares two decimals including special values.
+// -Inf is less than any other value and +Inf is greater than any other value,
+// negative zero is equal to zero.
+// Returns false as the second value if any of them is NaN, which is unordered.
+func (fd *FixedDecimal) CompareAny(rhs *FixedDecimal) (int, bool) {
+	if fd.IsNaN() || rhs.IsNaN() {
+		return 0, false
+	}
+	lrank, rrank := fd.infRank(), rhs.infRank()
+	if lrank != 0 || rrank != 0 {
+		return cmpInt(lrank, rrank), true
+	}
+	return fd.cmpFinite(rhs), true
+}
+
+// CompareTotal compares two decimals with total ordering of General Decimal
+// Arithmetic specification:
+//
+//	-NaN < -Inf < negative numbers < -0 < 0 < positive numbers < +Inf < +NaN
+//
+// Equal numbers with different scale are ordered by exponent, e.g.
+// 1.20 < 1.2 and -1.2 < -1.20.
+func (fd *FixedDecimal) CompareTotal(rhs *FixedDecimal) int {
+	lneg, rneg := fd.IsNeg(), rhs.IsNeg()
+	if lneg != rneg {
+		if lneg {
+			return -1
+		}
+		return 1
+	}
+	sign := 1
+	if lneg {
+		sign = -1
+	}
+	lnan, rnan := fd.IsNaN(), rhs.IsNaN()
+	if lnan || rnan {
+		if lnan && rnan {
+			return 0
+		}
+		if lnan {
+			return sign
+		}
+		return -sign
+	}
+	linf, rinf := fd.IsInf(), rhs.IsInf()
+	if linf || rinf {
+		if linf && rinf {
+			return 0
+		}
+		if linf {
+			return sign
+		}
+		return -sign
+	}
+	if c := cmpAbs(fd, rhs); c != 0 {
+		return c * sign
+	}
+	// more fractional digits means smaller exponent
+	return cmpInt(int(rhs.Frac()), int(fd.Frac())) * sign
+}
+
+// Equal returns true if two decimals are numerically equal.
+// NaN is not equal to any value including itself.
+func (fd *FixedDecimal) Equal(rhs *FixedDecimal) bool {
+	c, ok := fd.CompareAny(rhs)
+	return ok && c == 0
+}
+
+// Less returns true if this decimal is less than rhs.
+func (fd *FixedDecimal) Less(rhs *FixedDecimal) bool {
+	c, ok := fd.CompareAny(rhs)
+	return ok && c < 0
+}
+
+// LessEqual returns true if this decimal is less than or equal to rhs.
+func (fd *FixedDecimal) LessEqual(rhs *FixedDecimal) bool {
+	c, ok := fd.CompareAny(rhs)
+	return ok && c <= 0
+}
+
+// Greater returns true if this decimal is greater than rhs.
+func (fd *FixedDecimal) Greater(rhs *FixedDecimal) bool {
+	c, ok := fd.CompareAny(rhs)
+	return ok && c > 0
+}
+
+// GreaterEqual returns true if this decimal is greater than or equal to rhs.
+func (fd *FixedDecimal) GreaterEqual(rhs *FixedDecimal) bool {
+	c, ok := fd.CompareAny(rhs)
+	return ok && c >= 0
+}
+
+// infRank returns -1 for -Inf, 1 for +Inf and 0 for others.
+func (fd *FixedDecimal) infRank() int {
+	if !fd.IsInf() {
+		return 0
+	}
+	if fd.IsNeg() {
+		return -1
+	}
+	return 1
+}
+
+// cmpFinite compares two finite decimals, regardless of sign of zero.
+func (fd *FixedDecimal) cmpFinite(rhs *FixedDecimal) int {
+	lzero, rzero := fd.allUnitsZero(), rhs.allUnitsZero()
+	switch {
+	case lzero && rzero:
+		return 0
+	case lzero:
+		if rhs.IsNeg() {
+			return 1
+		}
+		return -1
+	case rzero:
+		if fd.IsNeg() {
+			return -1
+		}
+		return 1
+	}
+	return fd.Compare(rhs)
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
 func cmpAbs(lhs *FixedDecimal, rhs *FixedDecimal) int {
 	liu, lfu := lhs.IntgUnits(), lhs.FracUnits()
 	riu, rfu := rhs.IntgUnits(), rhs.FracUnits()
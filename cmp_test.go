@@ -0,0 +1,79 @@
+package fxd
+
+import "testing"
+
+// parseSpecial parses decimal, including "-Inf" and "-NaN" which cannot
+// be parsed by FromAsciiString.
+func parseSpecial(s string) FixedDecimal {
+	var fd FixedDecimal
+	fd.SetZero()
+	switch s {
+	case "NaN":
+		fd.setNaN()
+	case "-NaN":
+		fd.setNaN()
+		fd.setNeg()
+	case "Inf":
+		fd.setInf()
+	case "-Inf":
+		fd.setInf()
+		fd.setNeg()
+	default:
+		return mustParse(s)
+	}
+	return fd
+}
+
+func TestDecimalCompareAny(t *testing.T) {
+	type tcase struct {
+		lhs, rhs string
+		expected int
+		ok       bool
+	}
+	for _, c := range []tcase{
+		{"1", "2", -1, true},
+		{"-1", "-2", 1, true},
+		{"1.20", "1.2", 0, true},
+		{"-0", "0", 0, true},
+		{"-0.00", "0", 0, true},
+		{"-0", "-1", 1, true},
+		{"0", "0.001", -1, true},
+		{"Inf", "99999999999999999999999999999999999999999999999999999999999999999", 1, true},
+		{"-Inf", "-99999999999999999999999999999999999999999999999999999999999999999", -1, true},
+		{"-Inf", "Inf", -1, true},
+		{"Inf", "Inf", 0, true},
+		{"-Inf", "-Inf", 0, true},
+		{"NaN", "0", 0, false},
+		{"0", "NaN", 0, false},
+		{"NaN", "NaN", 0, false},
+		{"NaN", "Inf", 0, false},
+	} {
+		lhs, rhs := parseSpecial(c.lhs), parseSpecial(c.rhs)
+		actual, ok := lhs.CompareAny(&rhs)
+		if actual != c.expected || ok != c.ok {
+			t.Fatalf("compare %v %v mismatch: actual=%v,%v, expected=%v,%v", c.lhs, c.rhs, actual, ok, c.expected, c.ok)
+		}
+		eq, lt, le, gt, ge := lhs.Equal(&rhs), lhs.Less(&rhs), lhs.LessEqual(&rhs), lhs.Greater(&rhs), lhs.GreaterEqual(&rhs)
+		if eq != (ok && c.expected == 0) || lt != (ok && c.expected < 0) || le != (ok && c.expected <= 0) ||
+			gt != (ok && c.expected > 0) || ge != (ok && c.expected >= 0) {
+			t.Fatalf("predicate %v %v mismatch: %v %v %v %v %v", c.lhs, c.rhs, eq, lt, le, gt, ge)
+		}
+	}
+}
+
+func TestDecimalCompareTotal(t *testing.T) {
+	// in ascending total order
+	ordered := []string{
+		"-NaN", "-Inf", "-100", "-1.2", "-1.20", "-1.200", "-0.5", "-0", "-0.00",
+		"0.00", "0", "0.5", "1.200", "1.20", "1.2", "100", "Inf", "NaN",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			lhs, rhs := parseSpecial(ordered[i]), parseSpecial(ordered[j])
+			actual := lhs.CompareTotal(&rhs)
+			if expected := cmpInt(i, j); actual != expected {
+				t.Fatalf("compare %v %v mismatch: actual=%v, expected=%v", ordered[i], ordered[j], actual, expected)
+			}
+		}
+	}
+}
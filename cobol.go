@@ -0,0 +1,257 @@
+// COBOL packed decimal (COMP-3) and zoned decimal
+//
+// Both formats store the unscaled integer of picture S9(p-s)V9(s),
+// the decimal point is implied and not stored.
+//
+// Packed decimal stores two digits per byte, and the sign in the last
+// nibble: C(positive), D(negative) or F(unsigned), A and E are accepted
+// as positive, B as negative.
+// A leading zero nibble pads the value if the precision is even.
+//
+// Zoned decimal stores one digit per byte, and the sign is overpunched
+// on the last byte.
+// In EBCDIC, digit is 0xF0-0xF9 and the zone of last byte is replaced
+// by the sign nibble.
+// In ASCII, digit is '0'-'9', the last byte is '{', 'A'-'I' if positive,
+// and '}', 'J'-'R' if negative. 'p'-'y' is also accepted as negative.
+package fxd
+
+// CobolPic describes COBOL numeric picture S9(Prec-Scale)V9(Scale).
+type CobolPic struct {
+	Prec   int  // total number of digits
+	Scale  int  // number of digits after implied decimal point
+	Signed bool // picture has leading S
+}
+
+type ZonedCharset uint8
+
+const (
+	ZonedEBCDIC ZonedCharset = iota
+	ZonedASCII
+)
+
+const (
+	packedSignPos      = 0xC
+	packedSignNeg      = 0xD
+	packedSignUnsigned = 0xF
+	ebcdicZone         = 0xF0
+)
+
+func (pic CobolPic) validate() error {
+	if pic.Prec <= 0 || pic.Prec > MaxDigits || pic.Scale < 0 || pic.Scale > MaxFrac || pic.Scale > pic.Prec {
+		return DecErrInvalidType
+	}
+	return nil
+}
+
+// PackedLen returns byte length of packed decimal with given picture.
+func (pic CobolPic) PackedLen() int {
+	return pic.Prec/2 + 1
+}
+
+// picDigits stores unscaled digits of this decimal with given picture into coef.
+func (fd *FixedDecimal) picDigits(coef []int32, pic CobolPic) error {
+	if err := pic.validate(); err != nil {
+		return err
+	}
+	if err := fd.unscaledUnits(coef, pic.Prec, pic.Scale); err != nil {
+		return err
+	}
+	if !pic.Signed && fd.IsNeg() && unitsNonZero(coef) {
+		return DecErrInvalidValue
+	}
+	return nil
+}
+
+// unitsDigit returns i-th digit of little-endian units, from least significant one.
+func unitsDigit(coef []int32, i int) byte {
+	return byte(int(coef[div9(i)]) / pow10[mod9(i)] % 10)
+}
+
+// AppendPacked appends this decimal as packed decimal of given picture to buffer.
+// Fractional digits beyond scale are rounded.
+// Returns DecErrOverflow if the value does not fit the picture, or
+// DecErrInvalidValue if negative value is stored with unsigned picture.
+func (fd *FixedDecimal) AppendPacked(buf []byte, pic CobolPic) ([]byte, error) {
+	var coef [DoubleMaxUnits]int32
+	if err := fd.picDigits(coef[:], pic); err != nil {
+		return buf, err
+	}
+	var sign byte = packedSignUnsigned
+	if pic.Signed {
+		sign = packedSignPos
+		if fd.IsNeg() && unitsNonZero(coef[:]) {
+			sign = packedSignNeg
+		}
+	}
+	n := pic.PackedLen()
+	start := len(buf)
+	buf = append(buf, make([]byte, n)...)
+	packed := buf[start:]
+	packed[n-1] = unitsDigit(coef[:], 0)<<4 | sign
+	for i, d := n-2, 1; i >= 0; i, d = i-1, d+2 {
+		packed[i] = unitsDigit(coef[:], d+1)<<4 | unitsDigit(coef[:], d)
+	}
+	return buf, nil
+}
+
+// DecimalFromPacked creates a new decimal from packed decimal of given picture.
+func DecimalFromPacked(bs []byte, pic CobolPic) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromPacked(bs, pic)
+	return fd, err
+}
+
+// FromPacked parses packed decimal of given picture and set value to current decimal.
+// Returns DecErrInvalidDigit if any digit or sign nibble is invalid, or
+// sign is negative with unsigned picture.
+func (fd *FixedDecimal) FromPacked(bs []byte, pic CobolPic) error {
+	if err := pic.validate(); err != nil {
+		return err
+	}
+	if len(bs) != pic.PackedLen() {
+		return DecErrConversionSyntax
+	}
+	if pic.Prec%2 == 0 && bs[0]>>4 != 0 { // padding nibble
+		return DecErrInvalidDigit
+	}
+	var coef [DoubleMaxUnits]int32
+	for i, b := range bs {
+		hi, lo := b>>4, b&0xf
+		if hi > 9 {
+			return DecErrInvalidDigit
+		}
+		unitsMulAdd(coef[:], 10, int64(hi))
+		if i == len(bs)-1 {
+			break
+		}
+		if lo > 9 {
+			return DecErrInvalidDigit
+		}
+		unitsMulAdd(coef[:], 10, int64(lo))
+	}
+	var neg bool
+	switch bs[len(bs)-1] & 0xf {
+	case 0xA, 0xC, 0xE, 0xF:
+	case 0xB, 0xD:
+		neg = true
+	default:
+		return DecErrInvalidDigit
+	}
+	if neg && !pic.Signed {
+		return DecErrInvalidDigit
+	}
+	return fd.setCoefUnits(coef[:], pic.Scale, neg)
+}
+
+// AppendZoned appends this decimal as zoned decimal of given picture and charset
+// to buffer. Fractional digits beyond scale are rounded.
+// Returns DecErrOverflow if the value does not fit the picture, or
+// DecErrInvalidValue if negative value is stored with unsigned picture.
+func (fd *FixedDecimal) AppendZoned(buf []byte, pic CobolPic, charset ZonedCharset) ([]byte, error) {
+	var coef [DoubleMaxUnits]int32
+	if err := fd.picDigits(coef[:], pic); err != nil {
+		return buf, err
+	}
+	neg := fd.IsNeg() && unitsNonZero(coef[:])
+	for i := pic.Prec - 1; i >= 0; i-- {
+		d := unitsDigit(coef[:], i)
+		if charset == ZonedASCII {
+			if i == 0 && pic.Signed {
+				buf = append(buf, asciiOverpunch(d, neg))
+			} else {
+				buf = append(buf, '0'+d)
+			}
+			continue
+		}
+		if i == 0 && pic.Signed {
+			if neg {
+				buf = append(buf, packedSignNeg<<4|d)
+			} else {
+				buf = append(buf, packedSignPos<<4|d)
+			}
+		} else {
+			buf = append(buf, ebcdicZone|d)
+		}
+	}
+	return buf, nil
+}
+
+func asciiOverpunch(d byte, neg bool) byte {
+	if neg {
+		if d == 0 {
+			return '}'
+		}
+		return 'J' + d - 1
+	}
+	if d == 0 {
+		return '{'
+	}
+	return 'A' + d - 1
+}
+
+// DecimalFromZoned creates a new decimal from zoned decimal of given picture and charset.
+func DecimalFromZoned(bs []byte, pic CobolPic, charset ZonedCharset) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromZoned(bs, pic, charset)
+	return fd, err
+}
+
+// FromZoned parses zoned decimal of given picture and charset, and set value
+// to current decimal.
+// Returns DecErrInvalidDigit if any digit or sign is invalid, or sign is
+// negative with unsigned picture.
+func (fd *FixedDecimal) FromZoned(bs []byte, pic CobolPic, charset ZonedCharset) error {
+	if err := pic.validate(); err != nil {
+		return err
+	}
+	if len(bs) != pic.Prec {
+		return DecErrConversionSyntax
+	}
+	var coef [DoubleMaxUnits]int32
+	var neg bool
+	last := len(bs) - 1
+	for i, b := range bs {
+		var d byte
+		if charset == ZonedASCII {
+			switch {
+			case b >= '0' && b <= '9':
+				d = b - '0'
+			case i != last:
+				return DecErrInvalidDigit
+			case b == '{':
+			case b >= 'A' && b <= 'I':
+				d = b - 'A' + 1
+			case b == '}':
+				neg = true
+			case b >= 'J' && b <= 'R':
+				d, neg = b-'J'+1, true
+			case b >= 'p' && b <= 'y':
+				d, neg = b-'p', true
+			default:
+				return DecErrInvalidDigit
+			}
+		} else {
+			zone := b >> 4
+			d = b & 0xf
+			if d > 9 {
+				return DecErrInvalidDigit
+			}
+			switch {
+			case zone == 0xF:
+			case i != last:
+				return DecErrInvalidDigit
+			case zone == 0xA || zone == 0xC || zone == 0xE:
+			case zone == 0xB || zone == 0xD:
+				neg = true
+			default:
+				return DecErrInvalidDigit
+			}
+		}
+		unitsMulAdd(coef[:], 10, int64(d))
+	}
+	if neg && !pic.Signed {
+		return DecErrInvalidDigit
+	}
+	return fd.setCoefUnits(coef[:], pic.Scale, neg)
+}
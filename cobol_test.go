@@ -0,0 +1,159 @@
+package fxd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecimalPacked(t *testing.T) {
+	type tcase struct {
+		input    string
+		pic      CobolPic
+		expected []byte
+		output   string
+	}
+	for _, c := range []tcase{
+		{"0", CobolPic{3, 0, true}, []byte{0x00, 0x0C}, "0"},
+		{"123", CobolPic{3, 0, true}, []byte{0x12, 0x3C}, "123"},
+		{"-123", CobolPic{3, 0, true}, []byte{0x12, 0x3D}, "-123"},
+		{"123", CobolPic{3, 0, false}, []byte{0x12, 0x3F}, "123"},
+		{"12.34", CobolPic{4, 2, true}, []byte{0x01, 0x23, 0x4C}, "12.34"},
+		{"-1.235", CobolPic{4, 2, true}, []byte{0x00, 0x12, 0x4D}, "-1.24"},
+		{"-0.001", CobolPic{4, 2, true}, []byte{0x00, 0x00, 0x0C}, "0.00"},
+		{"1.5", CobolPic{5, 3, true}, []byte{0x01, 0x50, 0x0C}, "1.500"},
+		{"12345678901234567890.12345", CobolPic{25, 5, true},
+			[]byte{0x12, 0x34, 0x56, 0x78, 0x90, 0x12, 0x34, 0x56, 0x78, 0x90, 0x12, 0x34, 0x5C},
+			"12345678901234567890.12345"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		actual, err := fd.AppendPacked(nil, c.pic)
+		if err != nil || !bytes.Equal(actual, c.expected) {
+			t.Fatalf("packed %v mismatch: actual=%x, expected=%x, err=%v", c.input, actual, c.expected, err)
+		}
+		fd2, err := DecimalFromPacked(actual, c.pic)
+		if err != nil || fd2.ToString(-1) != c.output {
+			t.Fatalf("packed %v mismatch: actual=%v, expected=%v, err=%v", c.input, fd2.ToString(-1), c.output, err)
+		}
+	}
+	fd, _ := DecimalFromAsciiString("1234")
+	if _, err := fd.AppendPacked(nil, CobolPic{3, 0, true}); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	fd, _ = DecimalFromAsciiString("-1")
+	if _, err := fd.AppendPacked(nil, CobolPic{3, 0, false}); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := fd.AppendPacked(nil, CobolPic{3, 4, true}); err != DecErrInvalidType {
+		t.Fatalf("failed %v", err)
+	}
+	// alternative sign nibbles
+	for _, c := range []struct {
+		input    []byte
+		expected string
+	}{
+		{[]byte{0x12, 0x3A}, "123"},
+		{[]byte{0x12, 0x3B}, "-123"},
+		{[]byte{0x12, 0x3E}, "123"},
+		{[]byte{0x12, 0x3F}, "123"},
+	} {
+		fd, err := DecimalFromPacked(c.input, CobolPic{3, 0, true})
+		if err != nil || fd.ToString(-1) != c.expected {
+			t.Fatalf("packed %x mismatch: actual=%v, expected=%v, err=%v", c.input, fd.ToString(-1), c.expected, err)
+		}
+	}
+	// invalid nibbles
+	for _, c := range []struct {
+		input []byte
+		pic   CobolPic
+	}{
+		{[]byte{0x1A, 0x3C}, CobolPic{3, 0, true}},
+		{[]byte{0xA2, 0x3C}, CobolPic{3, 0, true}},
+		{[]byte{0x12, 0xAC}, CobolPic{3, 0, true}},
+		{[]byte{0x12, 0x39}, CobolPic{3, 0, true}},
+		{[]byte{0x10, 0x00, 0x0C}, CobolPic{4, 0, true}},
+		{[]byte{0x12, 0x3D}, CobolPic{3, 0, false}}, // negative sign of unsigned picture
+		{[]byte{0x12, 0x3B}, CobolPic{3, 0, false}},
+	} {
+		if _, err := DecimalFromPacked(c.input, c.pic); err != DecErrInvalidDigit {
+			t.Fatalf("packed %x mismatch: err=%v", c.input, err)
+		}
+	}
+	if _, err := DecimalFromPacked([]byte{0x12, 0x34, 0x5C}, CobolPic{3, 0, true}); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecimalZoned(t *testing.T) {
+	type tcase struct {
+		input   string
+		pic     CobolPic
+		charset ZonedCharset
+		encoded []byte
+		output  string
+	}
+	for _, c := range []tcase{
+		{"123", CobolPic{3, 0, true}, ZonedEBCDIC, []byte{0xF1, 0xF2, 0xC3}, "123"},
+		{"-123", CobolPic{3, 0, true}, ZonedEBCDIC, []byte{0xF1, 0xF2, 0xD3}, "-123"},
+		{"123", CobolPic{3, 0, false}, ZonedEBCDIC, []byte{0xF1, 0xF2, 0xF3}, "123"},
+		{"-1.5", CobolPic{4, 2, true}, ZonedEBCDIC, []byte{0xF0, 0xF1, 0xF5, 0xD0}, "-1.50"},
+		{"123", CobolPic{3, 0, true}, ZonedASCII, []byte("12C"), "123"},
+		{"-123", CobolPic{3, 0, true}, ZonedASCII, []byte("12L"), "-123"},
+		{"120", CobolPic{3, 0, true}, ZonedASCII, []byte("12{"), "120"},
+		{"-120", CobolPic{3, 0, true}, ZonedASCII, []byte("12}"), "-120"},
+		{"123", CobolPic{3, 0, false}, ZonedASCII, []byte("123"), "123"},
+		{"0.129", CobolPic{3, 2, true}, ZonedASCII, []byte("01C"), "0.13"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		actual, err := fd.AppendZoned(nil, c.pic, c.charset)
+		if err != nil || !bytes.Equal(actual, c.encoded) {
+			t.Fatalf("zoned %v mismatch: actual=%x, expected=%x, err=%v", c.input, actual, c.encoded, err)
+		}
+		fd2, err := DecimalFromZoned(actual, c.pic, c.charset)
+		if err != nil || fd2.ToString(-1) != c.output {
+			t.Fatalf("zoned %v mismatch: actual=%v, expected=%v, err=%v", c.input, fd2.ToString(-1), c.output, err)
+		}
+	}
+	fd, err := DecimalFromZoned([]byte("12s"), CobolPic{3, 1, true}, ZonedASCII)
+	if err != nil || fd.ToString(-1) != "-12.3" {
+		t.Fatalf("failed %v %v", fd.ToString(-1), err)
+	}
+	for _, c := range []struct {
+		input   []byte
+		charset ZonedCharset
+	}{
+		{[]byte{0xF1, 0xC2, 0xC3}, ZonedEBCDIC},
+		{[]byte{0xF1, 0xF2, 0xCA}, ZonedEBCDIC},
+		{[]byte{0xF1, 0xF2, 0x13}, ZonedEBCDIC},
+		{[]byte("1C3"), ZonedASCII},
+		{[]byte("12S"), ZonedASCII},
+		{[]byte("12 "), ZonedASCII},
+	} {
+		if _, err := DecimalFromZoned(c.input, CobolPic{3, 0, true}, c.charset); err != DecErrInvalidDigit {
+			t.Fatalf("zoned %x mismatch: err=%v", c.input, err)
+		}
+	}
+	// negative sign of unsigned picture
+	for _, c := range []struct {
+		input   []byte
+		charset ZonedCharset
+	}{
+		{[]byte{0xF1, 0xF2, 0xD3}, ZonedEBCDIC},
+		{[]byte("12L"), ZonedASCII},
+		{[]byte("12}"), ZonedASCII},
+		{[]byte("12s"), ZonedASCII},
+	} {
+		if _, err := DecimalFromZoned(c.input, CobolPic{3, 0, false}, c.charset); err != DecErrInvalidDigit {
+			t.Fatalf("zoned %x mismatch: err=%v", c.input, err)
+		}
+	}
+	if fd, err := DecimalFromZoned([]byte{0xF1, 0xF2, 0xC3}, CobolPic{3, 0, false}, ZonedEBCDIC); err != nil || fd.ToString(-1) != "123" {
+		t.Fatalf("failed %v %v", fd.ToString(-1), err)
+	}
+	if fd, err := DecimalFromPacked([]byte{0x12, 0x3C}, CobolPic{3, 0, false}); err != nil || fd.ToString(-1) != "123" {
+		t.Fatalf("failed %v %v", fd.ToString(-1), err)
+	}
+	fd, _ = DecimalFromAsciiString("-1")
+	if _, err := fd.AppendZoned(nil, CobolPic{3, 0, false}, ZonedASCII); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
@@ -35,3 +35,217 @@ func (fd *FixedDecimal) ToInt() int64 {
 	}
 	return sum
 }
+
+// coefUnits stores absolute value of this decimal multiplied by 10^scale,
+// a.k.a. the unscaled integer(coefficient) at given scale, into coef
+// as little-endian units. Fractional digits beyond scale are truncated.
+// coef must have at least MaxUnits units.
+// Returns false if coef is too small to store the result.
+func (fd *FixedDecimal) coefUnits(coef []int32, scale int) bool {
+	for i := range coef {
+		coef[i] = 0
+	}
+	fracUnits := fd.FracUnits()
+	copy(coef, fd.lsu[:fd.IntgUnits()+fracUnits])
+	k := scale - fracUnits*DigitsPerUnit
+	if k >= 0 {
+		return coefMulPow10(coef, k)
+	}
+	coefDivPow10(coef, -k)
+	return true
+}
+
+// setCoefUnits sets value coef*10^(-scale) to this decimal.
+// coef is little-endian units and will be modified.
+// Returns DecErrOverflow if the value cannot be stored.
+func (fd *FixedDecimal) setCoefUnits(coef []int32, scale int, neg bool) error {
+	if scale < 0 { // move digits to integral part
+		if !coefMulPow10(coef, -scale) {
+			return DecErrOverflow
+		}
+		scale = 0
+	}
+	if scale > MaxFrac {
+		return DecErrOverflow
+	}
+	fracUnits := getUnits(scale)
+	if !coefMulPow10(coef, fracUnits*DigitsPerUnit-scale) { // align fractional digits to units
+		return DecErrOverflow
+	}
+	n := len(coef)
+	for n > 0 && coef[n-1] == 0 {
+		n--
+	}
+	if n > MaxUnits {
+		return DecErrOverflow
+	}
+	if n > fracUnits && (n-fracUnits)*DigitsPerUnit-unitLeadingZeroes(coef[n-1]) > MaxDigits {
+		return DecErrOverflow
+	}
+	fd.setAlignedUnits(coef[:n], scale, neg)
+	return nil
+}
+
+// setAlignedUnits sets value coef*10^(-scale) to this decimal, where
+// fractional digits of coef are already aligned to units.
+// coef must fit MaxUnits units, integral digits are not checked against MaxDigits.
+func (fd *FixedDecimal) setAlignedUnits(coef []int32, scale int, neg bool) {
+	n := len(coef)
+	for n > 0 && coef[n-1] == 0 {
+		n--
+	}
+	if n == 0 {
+		fd.SetZero()
+		fd.frac = int8(scale)
+		return
+	}
+	fracUnits := getUnits(scale)
+	var intg int
+	if n > fracUnits {
+		intg = (n-fracUnits)*DigitsPerUnit - unitLeadingZeroes(coef[n-1])
+	}
+	fd.Reset()
+	copy(fd.lsu[:], coef[:n])
+	fd.intg = int8(intg)
+	fd.frac = int8(scale)
+	if neg {
+		fd.setNeg()
+	}
+}
+
+// coefMulPow10 multiplies little-endian units by 10^k in place.
+// Returns false on overflow.
+func coefMulPow10(coef []int32, k int) bool {
+	if k <= 0 {
+		return true
+	}
+	shift := div9(k)
+	if shift >= len(coef) {
+		return !unitsNonZero(coef)
+	}
+	if shift > 0 {
+		if unitsNonZero(coef[len(coef)-shift:]) {
+			return false
+		}
+		copy(coef[shift:], coef[:len(coef)-shift])
+		for i := 0; i < shift; i++ {
+			coef[i] = 0
+		}
+	}
+	if r := mod9(k); r > 0 {
+		return unitsMulAdd(coef, int64(pow10[r]), 0) == 0
+	}
+	return true
+}
+
+// coefDivPow10 divides little-endian units by 10^k in place, truncating
+// the discarded digits.
+// Returns true if any discarded digit is non-zero.
+func coefDivPow10(coef []int32, k int) bool {
+	if k <= 0 {
+		return false
+	}
+	shift := div9(k)
+	if shift >= len(coef) {
+		inexact := unitsNonZero(coef)
+		for i := range coef {
+			coef[i] = 0
+		}
+		return inexact
+	}
+	var inexact bool
+	if shift > 0 {
+		inexact = unitsNonZero(coef[:shift])
+		copy(coef, coef[shift:])
+		for i := len(coef) - shift; i < len(coef); i++ {
+			coef[i] = 0
+		}
+	}
+	if r := mod9(k); r > 0 {
+		if unitsDivRem(coef, int64(pow10[r])) != 0 {
+			inexact = true
+		}
+	}
+	return inexact
+}
+
+// unscaledUnits stores absolute value of this decimal as unscaled integer at
+// given scale into coef. Fractional digits beyond scale are rounded.
+// Returns DecErrOverflow if the unscaled integer has more digits than prec.
+func (fd *FixedDecimal) unscaledUnits(coef []int32, prec, scale int) error {
+	if fd.IsNaN() || fd.IsInf() {
+		return DecErrInvalidValue
+	}
+	src := fd
+	var rounded FixedDecimal
+	if scale < int(fd.Frac()) {
+		fd.RoundTo(&rounded, scale)
+		src = &rounded
+	}
+	if !src.coefUnits(coef, scale) || unitsDigits(coef) > prec {
+		return DecErrOverflow
+	}
+	return nil
+}
+
+// unscaledWords converts this decimal to unscaled integer at given scale,
+// as two's complement little-endian words. Fractional digits beyond scale
+// are rounded. Returns DecErrOverflow if the unscaled integer has more
+// digits than prec, or it cannot be held by words.
+func (fd *FixedDecimal) unscaledWords(words []uint64, prec, scale int) error {
+	var coef [DoubleMaxUnits]int32
+	if err := fd.unscaledUnits(coef[:], prec, scale); err != nil {
+		return err
+	}
+	if !unitsToWords(coef[:], words) || int64(words[len(words)-1]) < 0 { // sign bit is reserved
+		return DecErrOverflow
+	}
+	if fd.IsNeg() {
+		wordsNeg(words)
+	}
+	return nil
+}
+
+// fromUnscaledWords sets value of unscaled integer, stored as two's complement
+// little-endian words, with given scale to this decimal. words will be modified.
+// Returns DecErrOverflow if the unscaled integer has more digits than prec.
+func (fd *FixedDecimal) fromUnscaledWords(words []uint64, prec, scale int) error {
+	neg := int64(words[len(words)-1]) < 0
+	if neg {
+		wordsNeg(words)
+	}
+	var coef [DoubleMaxUnits]int32
+	if !wordsToUnits(words, coef[:]) || unitsDigits(coef[:]) > prec {
+		return DecErrOverflow
+	}
+	return fd.setCoefUnits(coef[:], trimCoefScale(coef[:], scale), neg)
+}
+
+// trimCoefScale removes trailing zeros of coef while scale exceeds
+// MaxFrac, so value with redundant fractional zeros still fits.
+// Returns the reduced scale.
+func trimCoefScale(coef []int32, scale int) int {
+	if !unitsNonZero(coef) {
+		return minInt(scale, MaxFrac)
+	}
+	for scale > MaxFrac && coef[0]%10 == 0 {
+		coefDivPow10(coef, 1)
+		scale--
+	}
+	return scale
+}
+
+// coefDivPow10HalfUp divides little-endian units by 10^k in place, rounding
+// the discarded digits with RoundHalfUp.
+// Returns true if any discarded digit is non-zero.
+func coefDivPow10HalfUp(coef []int32, k int) bool {
+	if k <= 0 {
+		return false
+	}
+	inexact := coefDivPow10(coef, k-1)
+	r := unitsDivRem(coef, 10)
+	if r >= 5 {
+		unitsMulAdd(coef, 1, 1)
+	}
+	return inexact || r != 0
+}
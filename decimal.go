@@ -174,18 +174,8 @@ func (fd *FixedDecimal) FromInt64(val int64, reset bool) {
 		return
 	}
 	neg := val < 0
-	if neg {
-		val = -val // convert to positive
-	}
-	var i int
-	for val != 0 {
-		q := val / Unit
-		r := val - q*Unit
-		fd.lsu[i] = int32(r)
-		i++
-		val = q
-	}
-	fd.intg = int8(i * DigitsPerUnit) // possible maximum integral digits
+	i := int64Units(absInt64(val), &fd.lsu) // magnitude of math.MinInt64 overflows int64
+	fd.intg = int8(i * DigitsPerUnit)       // possible maximum integral digits
 	if neg {
 		fd.setNeg()
 	}
@@ -212,12 +202,21 @@ func DecimalFromInt64(val int64) (fd FixedDecimal) {
 
 // DecimalNeg negates the input decimal.
 func DecimalNeg(fd *FixedDecimal) {
-	if fd.IsZero() {
-		return
-	}
-	if fd.IsNeg() {
-		fd.setPos()
-	} else {
-		fd.setNeg()
+	fd.Neg()
+}
+
+// DecimalType describes the SQL data type DECIMAL(Prec, Frac).
+// Prec is total number of digits, Frac is number of fractional digits.
+type DecimalType struct {
+	Prec int8
+	Frac int8
+}
+
+// NewDecimalType creates a decimal type with given precision and fractional
+// digits, and validates them against MaxDigits and MaxFrac.
+func NewDecimalType(prec, frac int) (DecimalType, error) {
+	if prec <= 0 || prec > MaxDigits || frac < 0 || frac > MaxFrac || frac > prec {
+		return DecimalType{}, DecErrInvalidType
 	}
+	return DecimalType{Prec: int8(prec), Frac: int8(frac)}, nil
 }
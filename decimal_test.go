@@ -315,6 +315,35 @@ func TestDecimalMul(t *testing.T) {
 	}
 }
 
+func TestDecimalMulChain(t *testing.T) {
+	type tcase struct {
+		input1   string
+		input2   string
+		input3   string
+		expected string
+	}
+	var fd1, fd2, fd3, res FixedDecimal
+	for _, c := range []tcase{
+		// product of 10 integral digits has only 9, its leading zero unit must not
+		// take units of fractional part in next multiplication
+		{"31622.7", "31622.7", "10000000000000000000000000000000000000000000000000000000.5", "9999951552900000000000000000000000000000000000000000000499997577.645"},
+		{"99999", "10000", "0.000000000000000000000000000001", "0.000000000000000000000999990000"},
+	} {
+		fd1.FromAsciiString(c.input1, true)
+		fd2.FromAsciiString(c.input2, true)
+		fd3.FromAsciiString(c.input3, true)
+		if err := DecimalMul(&fd1, &fd2, &res); err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if err := DecimalMul(&res, &fd3, &res); err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if actual := res.ToString(-1); actual != c.expected {
+			t.Fatalf("result mismatch: actual=%v, expected=%v", actual, c.expected)
+		}
+	}
+}
+
 func TestDecimalDiv(t *testing.T) {
 	type tcase struct {
 		input1, input2, expected string
@@ -429,6 +458,10 @@ func TestDecimalDiv(t *testing.T) {
 		{"400000000", "0.000000003", "133333333333333333.333333333333333333"},
 		{"4000000000", "0.000000003", "1333333333333333333.333333333333333333"},
 		{"1", "500000000.1", "0.000000001"},
+		{"999990999999.9", "99999.99999999", "9999909.999999999990999999"}, // quotient guess overflows unit
+		{"9999999.9999", "99999.99999999", "99.999999999009999999"},
+		{"4", "3.636363636363636363636366", "1.099999999999999999999999285000000000"}, // quotient guess adds back divisor
+		{"0.000000000000000000000004", "3.636363636363636363636366", "0.000000000000000000000001099999999999"},
 	} {
 		if err := fd1.FromAsciiString(c.input1, true); err != nil {
 			t.Fatalf("failed %v", err)
@@ -869,6 +902,9 @@ func TestDecimalRound(t *testing.T) {
 		{"0.999999999", 2, "1.00"},
 		{"0.999999999", 1, "1.0"},
 		{"0.999999999", 0, "1"},
+		{"1.5", 10, "1.5000000000"},
+		{"123456789123456789.5", 19, "123456789123456789.5000000000000000000"},
+		{"1.5", 19, "1.5000000000000000000"},
 	} {
 		if err := fd1.FromAsciiString(c.input1, true); err != nil {
 			t.Fatalf("failed %v", err)
@@ -888,6 +924,39 @@ func TestDecimalRound(t *testing.T) {
 	}
 }
 
+func TestDecimalRoundExtend(t *testing.T) {
+	type tcase struct {
+		dirty    string
+		input    string
+		frac     int
+		expected string
+	}
+	var fd1 FixedDecimal
+	var fd2 FixedDecimal
+	for _, c := range []tcase{
+		{"0", "1.5", 10, "1.5000000000"},
+		{"999999999999999999.999999999999999999", "1.5", 19, "1.5000000000000000000"},
+		{"999999999999999999.999999999999999999", "0.000000001", 30, "0.000000001000000000000000000000"},
+		{"-123456789", "-2", 9, "-2.000000000"},
+	} {
+		// result holds units of previous value, which must not leak into extended units
+		if err := fd2.FromAsciiString(c.dirty, true); err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if err := fd1.FromAsciiString(c.input, true); err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		fd1.RoundTo(&fd2, c.frac)
+		if actual := fd2.ToString(-1); actual != c.expected {
+			t.Fatalf("RoundTo mismatch: actual=%v, expected=%v", actual, c.expected)
+		}
+		fd1.Round(c.frac)
+		if actual := fd1.ToString(-1); actual != c.expected {
+			t.Fatalf("Round mismatch: actual=%v, expected=%v", actual, c.expected)
+		}
+	}
+}
+
 func TestDecimalFormat(t *testing.T) {
 	type tscase struct {
 		input    string
@@ -1112,6 +1181,8 @@ func TestDecimalMod(t *testing.T) {
 		{"1000000000000000001", "0.70298007", "0.07924142"},
 		{"1000000000000000001", "500000000.1", "300000001.1"},
 		{"0.1", "0.20000000001", "0.10000000000"},
+		{"999999999909.9", "999.9999999999", "909.9999999999"}, // quotient guess overflows unit
+		{"9999999999999", "9999.9999999999", "9999.0999999999"},
 	} {
 		if err := fd1.FromAsciiString(c.input1, true); err != nil {
 			t.Fatalf("failed %v", err)
@@ -1139,20 +1210,89 @@ func TestIterativeArith(t *testing.T) {
 	_ = c2[n-1]
 	for i := 2; i < n; i++ {
 		for j := 0; j < 1024; j++ {
-			lhs := c1[i]
-			if err := DecimalAdd(&lhs, &c2[i], &c1[i]); err != nil {
+			if err := DecimalAdd(&c1[i], &c2[i], &c1[i]); err != nil {
 				t.Fatalf("add error: %v", err)
 			}
 		}
 		for j := 0; j < 1024; j++ {
-			lhs := c1[i]
-			if err := DecimalSub(&lhs, &c2[i], &c1[i]); err != nil {
+			if err := DecimalSub(&c1[i], &c2[i], &c1[i]); err != nil {
 				t.Fatalf("add error: %v", err)
 			}
 		}
 	}
 }
 
+func TestArithAliasing(t *testing.T) {
+	type arithFunc func(lhs, rhs, result *FixedDecimal) error
+	ops := map[string]arithFunc{
+		"add": DecimalAdd,
+		"sub": DecimalSub,
+		"mul": DecimalMul,
+		"div": func(lhs, rhs, result *FixedDecimal) error {
+			return DecimalDiv(lhs, rhs, result, DivIncrFrac)
+		},
+		"mod": DecimalMod,
+	}
+	assigns := map[string]func(fd, rhs *FixedDecimal) error{
+		"add": (*FixedDecimal).AddAssign,
+		"sub": (*FixedDecimal).SubAssign,
+		"mul": (*FixedDecimal).MulAssign,
+		"div": func(fd, rhs *FixedDecimal) error {
+			return fd.DivAssign(rhs, DivIncrFrac)
+		},
+		"mod": (*FixedDecimal).ModAssign,
+	}
+	inputs := []string{"0", "1", "-1", "123.456", "-0.000000001", "999999999.999999999", "-1000000000000000000.5", "7"}
+	for name, op := range ops {
+		for _, ls := range inputs {
+			for _, rs := range inputs {
+				lhs, _ := DecimalFromAsciiString(ls)
+				rhs, _ := DecimalFromAsciiString(rs)
+				if rhs.IsZero() && (name == "div" || name == "mod") {
+					continue
+				}
+				var expected FixedDecimal
+				if err := op(&lhs, &rhs, &expected); err != nil {
+					t.Fatalf("%v %v %v failed: %v", name, ls, rs, err)
+				}
+				// result is lhs
+				x, y := lhs, rhs
+				if err := op(&x, &y, &x); err != nil || x.ToString(-1) != expected.ToString(-1) || y != rhs {
+					t.Fatalf("%v %v %v lhs aliasing mismatch: actual=%v, expected=%v, err=%v", name, ls, rs, x.ToString(-1), expected.ToString(-1), err)
+				}
+				// result is rhs
+				x, y = lhs, rhs
+				if err := op(&x, &y, &y); err != nil || y.ToString(-1) != expected.ToString(-1) || x != lhs {
+					t.Fatalf("%v %v %v rhs aliasing mismatch: actual=%v, expected=%v, err=%v", name, ls, rs, y.ToString(-1), expected.ToString(-1), err)
+				}
+				// in-place method
+				x, y = lhs, rhs
+				if err := assigns[name](&x, &y); err != nil || x.ToString(-1) != expected.ToString(-1) || y != rhs {
+					t.Fatalf("%v %v %v assign mismatch: actual=%v, expected=%v, err=%v", name, ls, rs, x.ToString(-1), expected.ToString(-1), err)
+				}
+			}
+			x, _ := DecimalFromAsciiString(ls)
+			if x.IsZero() && (name == "div" || name == "mod") {
+				continue
+			}
+			var expected FixedDecimal
+			y := x
+			if err := op(&x, &y, &expected); err != nil {
+				t.Fatalf("%v %v failed: %v", name, ls, err)
+			}
+			// lhs and rhs are same
+			var actual FixedDecimal
+			if err := op(&x, &x, &actual); err != nil || actual.ToString(-1) != expected.ToString(-1) {
+				t.Fatalf("%v %v operand aliasing mismatch: actual=%v, expected=%v, err=%v", name, ls, actual.ToString(-1), expected.ToString(-1), err)
+			}
+			// lhs, rhs and result are all same
+			if err := op(&x, &x, &x); err != nil || x.ToString(-1) != expected.ToString(-1) {
+				t.Fatalf("%v %v full aliasing mismatch: actual=%v, expected=%v, err=%v", name, ls, x.ToString(-1), expected.ToString(-1), err)
+			}
+		}
+	}
+}
+
 func genRandDecimalArray(n int) []FixedDecimal {
 	fds := make([]FixedDecimal, n)
 	for i := range fds {
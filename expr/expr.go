@@ -0,0 +1,80 @@
+// Package expr parses and evaluates arithmetic expressions on
+// fixed-point decimals, e.g. pricing formulas configured by users:
+//
+//	round(price * (1 + tax/100), 2)
+//
+// An expression consists of decimal literals, variables, operators
+// + - * / with usual precedence, unary minus, parentheses and calls
+// of built-in functions:
+//
+//	round(x[, frac])  rounds half up to frac digits, 0 by default
+//	abs(x)            absolute value
+//	min(x, ...)       smallest argument
+//	max(x, ...)       largest argument
+//	mod(x, y)         remainder of x divided by y, with sign of x
+//
+// Literals are parsed by fxd.FixedDecimal.FromBytesString, exponent
+// such as 1.5e3 is allowed. Variable names are letters, digits and
+// underscores, not starting with a digit.
+//
+// Parse builds the syntax tree, and Compile turns it into a Program,
+// which can be evaluated many times with different variables.
+// Sub-expressions without variables are evaluated once in Compile.
+//
+// Errors of parsing, compiling and evaluation are *Error, with byte
+// offset in source and the fxd.DecErr, e.g. division by zero reports
+// offset of the '/' operator.
+package expr
+
+import (
+	"strconv"
+
+	"github.com/jiangzhe/fxd"
+)
+
+// Error is error of an expression at given position.
+type Error struct {
+	Pos int        // byte offset in source, starting from 0
+	Err fxd.DecErr // cause of the error
+}
+
+func (e *Error) Error() string {
+	return "pos " + strconv.Itoa(e.Pos) + ": " + e.Err.Error()
+}
+
+// Unwrap returns cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func errorAt(pos int, err error) error {
+	if de, ok := err.(fxd.DecErr); ok {
+		return &Error{Pos: pos, Err: de}
+	}
+	return &Error{Pos: pos, Err: fxd.DecErrInvalidValue}
+}
+
+// function is a built-in function.
+type function struct {
+	name    string
+	op      opcode
+	minArgs int
+	maxArgs int // -1 means no limit
+}
+
+var functions = []function{
+	{"round", opRound, 1, 2},
+	{"abs", opAbs, 1, 1},
+	{"min", opMin, 1, -1},
+	{"max", opMax, 1, -1},
+	{"mod", opMod, 2, 2},
+}
+
+func lookupFunction(name string) *function {
+	for i := range functions {
+		if functions[i].name == name {
+			return &functions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jiangzhe/fxd"
+)
+
+func vars(kvs ...string) map[string]fxd.FixedDecimal {
+	m := make(map[string]fxd.FixedDecimal)
+	for i := 0; i < len(kvs); i += 2 {
+		fd, err := fxd.DecimalFromAsciiString(kvs[i+1])
+		if err != nil {
+			panic(err)
+		}
+		m[kvs[i]] = fd
+	}
+	return m
+}
+
+func TestEval(t *testing.T) {
+	type tcase struct {
+		src      string
+		vars     map[string]fxd.FixedDecimal
+		expected string
+	}
+	for _, tc := range []tcase{
+		{"1", nil, "1"},
+		{"1 + 2 * 3", nil, "7"},
+		{"(1 + 2) * 3", nil, "9"},
+		{"10 - 4 - 3", nil, "3"},
+		{"1 / 3", nil, "0.333333333"},
+		{"1.0 / 4 * 2", nil, "0.500000000"},
+		{"-2 * -3", nil, "6"},
+		{"--2", nil, "2"},
+		{"+2 - +1", nil, "1"},
+		{"1.5e3 + .5", nil, "1500.5"},
+		{"2E-2", nil, "0.02"},
+		{"-0", nil, "0"},
+		{"round(price * (1 + tax/100), 2)", vars("price", "19.99", "tax", "8.25"), "21.64"},
+		{"round(price * (1 + tax/100), 2)", vars("price", "0.04", "tax", "0"), "0.04"},
+		{"round(2.5)", nil, "3"},
+		{"round(-2.5)", nil, "-3"},
+		{"round(-0.4)", nil, "0"},
+		{"round(1234.5, -2)", nil, "1200"},
+		{"round(x, n)", vars("x", "1.2345", "n", "3"), "1.235"},
+		{"abs(x - 10)", vars("x", "2.5"), "7.5"},
+		{"min(3, x, 2.5)", vars("x", "4"), "2.5"},
+		{"max(3, x, 2.5)", vars("x", "4"), "4"},
+		{"min(x)", vars("x", "-1"), "-1"},
+		{"mod(x, 3)", vars("x", "-10"), "-1"},
+		{"mod(10.5, 3)", nil, "1.5"},
+		{"a*a + b*b", vars("a", "3", "b", "4"), "25"},
+		{"a_1 + _b", vars("a_1", "1", "_b", "2"), "3"},
+		{" \tx\n", vars("x", "1.50"), "1.50"},
+	} {
+		prog, err := Compile(tc.src)
+		if err != nil {
+			t.Fatalf("compile %q failed: %v", tc.src, err)
+		}
+		res, err := prog.Eval(tc.vars)
+		if err != nil || res.ToString(-1) != tc.expected {
+			t.Fatalf("%q mismatch: actual=%v,%v, expected=%v", tc.src, res.ToString(-1), err, tc.expected)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	// constant operations are folded
+	prog, err := Compile("x * (1 + 2 * 3) + round(1 / 3, 2)")
+	if err != nil {
+		t.Fatalf("failed %v", err)
+	}
+	if len(prog.code) != 5 || len(prog.consts) != 2 || prog.consts[0].ToString(-1) != "7" || prog.consts[1].ToString(-1) != "0.33" {
+		t.Fatalf("failed %v %v", prog.code, prog.consts)
+	}
+	if names := prog.Vars(); len(names) != 1 || names[0] != "x" {
+		t.Fatalf("failed %v", names)
+	}
+	// program is reusable
+	for _, x := range []string{"1", "2.5", "-3"} {
+		expected := vars("x", x)["x"].MustMul(fxd.DecimalFromInt64(7)).MustAdd(prog.consts[1])
+		if res, err := prog.Eval(vars("x", x)); err != nil || res.Compare(&expected) != 0 {
+			t.Fatalf("failed %v %v", res.ToString(-1), err)
+		}
+	}
+	// same variable shares one slot
+	prog, _ = Compile("a + b * a")
+	if names := prog.Vars(); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("failed %v", names)
+	}
+	// expression compiles with different division precision
+	e, err := Parse("2 / 3")
+	if err != nil {
+		t.Fatalf("failed %v", err)
+	}
+	for _, tc := range []struct {
+		incrFrac int
+		expected string
+	}{{0, "0"}, {4, "0.666666666"}, {10, "0.666666666666666666"}, {4, "0.666666666"}} {
+		prog, err := e.Compile(tc.incrFrac)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if res, _ := prog.Eval(nil); res.ToString(-1) != tc.expected {
+			t.Fatalf("incrFrac %v mismatch: actual=%v, expected=%v", tc.incrFrac, res.ToString(-1), tc.expected)
+		}
+	}
+	if _, err := e.Compile(-1); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestError(t *testing.T) {
+	type tcase struct {
+		src  string
+		vars map[string]fxd.FixedDecimal
+		pos  int
+		err  fxd.DecErr
+	}
+	for _, tc := range []tcase{
+		// parsing
+		{"", nil, 0, fxd.DecErrConversionSyntax},
+		{"1 +", nil, 3, fxd.DecErrConversionSyntax},
+		{"1 2", nil, 2, fxd.DecErrConversionSyntax},
+		{"(1 + 2", nil, 6, fxd.DecErrConversionSyntax},
+		{"1 + 2)", nil, 5, fxd.DecErrConversionSyntax},
+		{"1 # 2", nil, 2, fxd.DecErrConversionSyntax},
+		{"1..2", nil, 0, fxd.DecErrConversionSyntax},
+		{"1e", nil, 1, fxd.DecErrConversionSyntax},
+		{"x + sqrt(2)", nil, 4, fxd.DecErrConversionSyntax},
+		{"round()", nil, 6, fxd.DecErrConversionSyntax},
+		{"mod(1)", nil, 0, fxd.DecErrConversionSyntax},
+		{"abs(1, 2)", nil, 0, fxd.DecErrConversionSyntax},
+		{"min(1,)", nil, 6, fxd.DecErrConversionSyntax},
+		// compiling
+		{"x + 1 / (2 - 2)", nil, 6, fxd.DecErrDivisionByZero},
+		{"mod(5, 0)", nil, 0, fxd.DecErrDivisionByZero},
+		{"round(1, 0.5)", nil, 0, fxd.DecErrInvalidValue},
+		{"round(1, 31)", nil, 0, fxd.DecErrInvalidValue},
+		{"round(1, -1e20)", nil, 0, fxd.DecErrInvalidValue},
+		// evaluation
+		{"price / qty", vars("price", "1", "qty", "0"), 6, fxd.DecErrDivisionByZero},
+		{"price * qty", vars("price", "1"), 8, fxd.DecErrInvalidValue},
+		{"1 + round(x, n)", vars("x", "1", "n", "-100"), 4, fxd.DecErrInvalidValue},
+		{"x * x", vars("x", "1e40"), 2, fxd.DecErrOverflow},
+	} {
+		var err error
+		prog, err := Compile(tc.src)
+		if err == nil {
+			_, err = prog.Eval(tc.vars)
+		}
+		var e *Error
+		if !errors.As(err, &e) || e.Pos != tc.pos || e.Err != tc.err || !errors.Is(err, tc.err) {
+			t.Fatalf("%q mismatch: actual=%v, expected=pos %v: %v", tc.src, err, tc.pos, tc.err)
+		}
+	}
+	nan, _ := fxd.DecimalFromAsciiString("NaN")
+	prog, _ := Compile("1 + x")
+	if _, err := prog.Eval(map[string]fxd.FixedDecimal{"x": nan}); err == nil || err.Error() != "pos 4: "+fxd.DecErrInvalidValue.Error() {
+		t.Fatalf("failed %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+// Parser of expressions
+//
+// Grammar, lowest precedence first:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary }
+//	unary   = { "+" | "-" } primary
+//	primary = number | name | name "(" expr { "," expr } ")" | "(" expr ")"
+package expr
+
+import "github.com/jiangzhe/fxd"
+
+// Expr is syntax tree of an expression.
+type Expr struct {
+	root *node
+}
+
+// node is an operation with its operands, or a leaf of literal or
+// variable.
+type node struct {
+	op   opcode
+	pos  int              // offset of operator, function name, literal or variable
+	val  fxd.FixedDecimal // value of opConst
+	name string           // name of opVar
+	args []*node
+}
+
+// Parse parses source of an expression.
+func Parse(src string) (*Expr, error) {
+	p := &parser{src: src}
+	p.next()
+	root := p.expr()
+	if p.err == nil && p.tok != tokEOF {
+		p.fail(p.pos)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Expr{root: root}, nil
+}
+
+type token uint8
+
+const (
+	tokEOF token = iota
+	tokNum
+	tokName
+	tokOp // one of + - * / ( ) ,
+)
+
+// parser is a recursive descent parser, the first error is recorded
+// and parsing stops at it.
+type parser struct {
+	src string
+	off int    // offset of next token
+	tok token  // current token
+	pos int    // offset of current token
+	lit string // text of current token
+	err error
+}
+
+// next scans next token.
+func (p *parser) next() {
+	for p.off < len(p.src) && isSpace(p.src[p.off]) {
+		p.off++
+	}
+	p.pos = p.off
+	if p.off == len(p.src) {
+		p.tok, p.lit = tokEOF, ""
+		return
+	}
+	c := p.src[p.off]
+	switch {
+	case isDigit(c) || c == '.':
+		p.tok = tokNum
+		p.off = scanNumber(p.src, p.off)
+	case isLetter(c):
+		p.tok = tokName
+		for p.off++; p.off < len(p.src) && (isLetter(p.src[p.off]) || isDigit(p.src[p.off])); p.off++ {
+		}
+	case c == '+' || c == '-' || c == '*' || c == '/' || c == '(' || c == ')' || c == ',':
+		p.tok = tokOp
+		p.off++
+	default:
+		p.fail(p.off)
+		p.tok = tokEOF
+	}
+	p.lit = p.src[p.pos:p.off]
+}
+
+// scanNumber returns end offset of number starting at off, which is
+// digits with optional dot, followed by optional exponent.
+func scanNumber(src string, off int) int {
+	for off < len(src) && (isDigit(src[off]) || src[off] == '.') {
+		off++
+	}
+	if off < len(src) && (src[off] == 'e' || src[off] == 'E') {
+		end := off + 1
+		if end < len(src) && (src[end] == '+' || src[end] == '-') {
+			end++
+		}
+		if end < len(src) && isDigit(src[end]) {
+			for off = end; off < len(src) && isDigit(src[off]); off++ {
+			}
+		}
+	}
+	return off
+}
+
+func (p *parser) fail(pos int) {
+	if p.err == nil {
+		p.err = &Error{Pos: pos, Err: fxd.DecErrConversionSyntax}
+	}
+}
+
+// expect consumes given operator.
+func (p *parser) expect(op string) bool {
+	if p.err != nil {
+		return false
+	}
+	if p.tok != tokOp || p.lit != op {
+		p.fail(p.pos)
+		return false
+	}
+	p.next()
+	return true
+}
+
+func (p *parser) expr() *node {
+	n := p.term()
+	for p.err == nil && p.tok == tokOp && (p.lit == "+" || p.lit == "-") {
+		op, pos := opAdd, p.pos
+		if p.lit == "-" {
+			op = opSub
+		}
+		p.next()
+		n = &node{op: op, pos: pos, args: []*node{n, p.term()}}
+	}
+	return n
+}
+
+func (p *parser) term() *node {
+	n := p.unary()
+	for p.err == nil && p.tok == tokOp && (p.lit == "*" || p.lit == "/") {
+		op, pos := opMul, p.pos
+		if p.lit == "/" {
+			op = opDiv
+		}
+		p.next()
+		n = &node{op: op, pos: pos, args: []*node{n, p.unary()}}
+	}
+	return n
+}
+
+func (p *parser) unary() *node {
+	if p.err == nil && p.tok == tokOp && (p.lit == "+" || p.lit == "-") {
+		neg, pos := p.lit == "-", p.pos
+		p.next()
+		n := p.unary()
+		if neg {
+			return &node{op: opNeg, pos: pos, args: []*node{n}}
+		}
+		return n
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() *node {
+	if p.err != nil {
+		return nil
+	}
+	pos, lit := p.pos, p.lit
+	switch p.tok {
+	case tokNum:
+		n := &node{op: opConst, pos: pos}
+		if err := n.val.FromBytesString([]byte(lit), true); err != nil {
+			p.err = errorAt(pos, err)
+			return nil
+		}
+		p.next()
+		return n
+	case tokName:
+		p.next()
+		if p.tok != tokOp || p.lit != "(" {
+			return &node{op: opVar, pos: pos, name: lit}
+		}
+		return p.call(pos, lit)
+	case tokOp:
+		if lit == "(" {
+			p.next()
+			n := p.expr()
+			p.expect(")")
+			return n
+		}
+	}
+	p.fail(pos)
+	return nil
+}
+
+// call parses arguments of function call, current token is "(".
+func (p *parser) call(pos int, name string) *node {
+	fn := lookupFunction(name)
+	if fn == nil {
+		p.fail(pos)
+		return nil
+	}
+	n := &node{op: fn.op, pos: pos}
+	p.next()
+	for p.err == nil {
+		n.args = append(n.args, p.expr())
+		if p.tok != tokOp || p.lit != "," {
+			break
+		}
+		p.next()
+	}
+	if !p.expect(")") {
+		return nil
+	}
+	if len(n.args) < fn.minArgs || fn.maxArgs >= 0 && len(n.args) > fn.maxArgs {
+		p.fail(pos)
+		return nil
+	}
+	return n
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
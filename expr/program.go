@@ -0,0 +1,219 @@
+// Compiled program of expression
+//
+// A program is instructions of a stack machine in postfix order.
+// Operations whose operands are all constants are evaluated during
+// compilation and replaced by the constant result, so only parts
+// depending on variables are left to evaluation.
+package expr
+
+import "github.com/jiangzhe/fxd"
+
+type opcode uint8
+
+const (
+	opConst opcode = iota // push constant
+	opVar                 // push variable
+	opNeg
+	opAdd
+	opSub
+	opMul
+	opDiv
+	opRound
+	opAbs
+	opMin
+	opMax
+	opMod
+)
+
+// instr is an instruction of program.
+type instr struct {
+	op  opcode
+	pos int // offset in source for error reporting
+	arg int // index of constant or variable, or number of operands
+}
+
+// Program is a compiled expression.
+// It's immutable and can be evaluated concurrently.
+type Program struct {
+	code     []instr
+	consts   []fxd.FixedDecimal
+	vars     []string
+	varPos   []int // offset of first occurrence of each variable
+	depth    int   // maximum depth of stack
+	incrFrac int
+}
+
+// Compile parses and compiles source of an expression, division
+// increases fxd.DivIncrFrac fractional digits.
+func Compile(src string) (*Program, error) {
+	e, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return e.Compile(fxd.DivIncrFrac)
+}
+
+// Compile compiles the expression, division increases incrFrac
+// fractional digits as fxd.DecimalDiv.
+// Errors of constant operations, e.g. 1/0, are returned here.
+// Returns fxd.DecErrInvalidValue if incrFrac is out of range.
+func (e *Expr) Compile(incrFrac int) (*Program, error) {
+	if incrFrac < 0 || incrFrac > fxd.MaxFrac {
+		return nil, fxd.DecErrInvalidValue
+	}
+	p := &Program{incrFrac: incrFrac}
+	root, err := p.fold(e.root)
+	if err != nil {
+		return nil, err
+	}
+	p.emit(root, 0)
+	return p, nil
+}
+
+// fold returns copy of n with constant operations evaluated, the
+// expression is untouched so it can be compiled again.
+func (p *Program) fold(n *node) (*node, error) {
+	if n.op == opConst || n.op == opVar {
+		return n, nil
+	}
+	folded := &node{op: n.op, pos: n.pos, args: make([]*node, len(n.args))}
+	allConst := true
+	for i := range n.args {
+		arg, err := p.fold(n.args[i])
+		if err != nil {
+			return nil, err
+		}
+		folded.args[i] = arg
+		allConst = allConst && arg.op == opConst
+	}
+	if !allConst {
+		return folded, nil
+	}
+	args := make([]fxd.FixedDecimal, len(n.args))
+	for i := range folded.args {
+		args[i] = folded.args[i].val
+	}
+	val, err := p.exec(n.op, args)
+	if err != nil {
+		return nil, errorAt(n.pos, err)
+	}
+	return &node{op: opConst, pos: n.pos, val: val}, nil
+}
+
+// emit appends instructions of n, whose result is at given depth of stack.
+func (p *Program) emit(n *node, depth int) {
+	for i := range n.args {
+		p.emit(n.args[i], depth+i)
+	}
+	in := instr{op: n.op, pos: n.pos, arg: len(n.args)}
+	switch n.op {
+	case opConst:
+		in.arg = len(p.consts)
+		p.consts = append(p.consts, n.val)
+	case opVar:
+		in.arg = -1
+		for i := range p.vars {
+			if p.vars[i] == n.name {
+				in.arg = i
+			}
+		}
+		if in.arg < 0 {
+			in.arg = len(p.vars)
+			p.vars = append(p.vars, n.name)
+			p.varPos = append(p.varPos, n.pos)
+		}
+	}
+	if depth+1 > p.depth {
+		p.depth = depth + 1
+	}
+	p.code = append(p.code, in)
+}
+
+// Vars returns names of variables in order of first occurrence.
+func (p *Program) Vars() []string {
+	return append([]string(nil), p.vars...)
+}
+
+// Eval evaluates the program with given variables.
+// Missing variable and NaN or Inf value fail with fxd.DecErrInvalidValue
+// at its first occurrence.
+func (p *Program) Eval(vars map[string]fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	values := make([]fxd.FixedDecimal, len(p.vars))
+	for i, name := range p.vars {
+		v, ok := vars[name]
+		if !ok || v.IsNaN() || v.IsInf() {
+			return fxd.FixedDecimal{}, &Error{Pos: p.varPos[i], Err: fxd.DecErrInvalidValue}
+		}
+		values[i] = v
+	}
+	stack := make([]fxd.FixedDecimal, 0, p.depth)
+	for _, in := range p.code {
+		switch in.op {
+		case opConst:
+			stack = append(stack, p.consts[in.arg])
+		case opVar:
+			stack = append(stack, values[in.arg])
+		default:
+			top := len(stack) - in.arg
+			res, err := p.exec(in.op, stack[top:])
+			if err != nil {
+				return fxd.FixedDecimal{}, errorAt(in.pos, err)
+			}
+			stack = append(stack[:top], res)
+		}
+	}
+	return stack[0], nil
+}
+
+// exec executes operation on operands.
+func (p *Program) exec(op opcode, args []fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	var res fxd.FixedDecimal
+	var err error
+	switch op {
+	case opNeg:
+		args[0].NegTo(&res)
+	case opAdd:
+		err = fxd.DecimalAdd(&args[0], &args[1], &res)
+	case opSub:
+		err = fxd.DecimalSub(&args[0], &args[1], &res)
+	case opMul:
+		err = fxd.DecimalMul(&args[0], &args[1], &res)
+	case opDiv:
+		err = fxd.DecimalDiv(&args[0], &args[1], &res, p.incrFrac)
+	case opMod:
+		err = fxd.DecimalMod(&args[0], &args[1], &res)
+	case opAbs:
+		args[0].AbsTo(&res)
+	case opMin, opMax:
+		res = args[0]
+		for i := 1; i < len(args); i++ {
+			if c := args[i].Compare(&res); op == opMin && c < 0 || op == opMax && c > 0 {
+				res = args[i]
+			}
+		}
+	case opRound:
+		frac := 0
+		if len(args) > 1 {
+			if frac, err = roundFrac(&args[1]); err != nil {
+				return res, err
+			}
+		}
+		args[0].RoundTo(&res, frac)
+	}
+	if err == nil && res.Sign() == 0 { // no negative zero
+		res.Abs()
+	}
+	return res, err
+}
+
+// roundFrac converts frac argument of round to int, which must be
+// an integer from -fxd.MaxDigits to fxd.MaxFrac.
+func roundFrac(fd *fxd.FixedDecimal) (int, error) {
+	if !fd.IsInteger() {
+		return 0, fxd.DecErrInvalidValue
+	}
+	if fd.CompareInt64(int64(-fxd.MaxDigits)) < 0 || fd.CompareInt64(fxd.MaxFrac) > 0 {
+		return 0, fxd.DecErrInvalidValue
+	}
+	return int(fd.ToInt()), nil
+}
@@ -0,0 +1,105 @@
+// Amortization schedules
+//
+// A schedule has one row per period, payments are at end of each
+// period. Interest of each row is the rounded balance of previous row
+// times the periodic rate, rounded half up to scale of Context, so
+// every amount of the schedule is exact at that scale.
+// Rounding residue of the fixed amounts accumulates in the balance,
+// and is absorbed by principal of the final row, which always pays
+// off the whole balance.
+//
+// Unlike TVM functions, amounts of schedules are not signed by cash
+// direction: principal of the loan and all amounts of rows are
+// non-negative.
+package fin
+
+import "github.com/jiangzhe/fxd"
+
+// AmortMethod is repayment method of a loan.
+type AmortMethod uint8
+
+const (
+	// AmortFixedPayment pays the same amount each period, the annuity
+	// of PMT, with decreasing interest and increasing principal.
+	AmortFixedPayment AmortMethod = iota
+	// AmortFixedPrincipal repays the same principal each period,
+	// with decreasing interest and payment.
+	AmortFixedPrincipal
+	// AmortInterestOnly pays interest only, and repays the whole
+	// principal in the final period.
+	AmortInterestOnly
+)
+
+// AmortRow is one period of an amortization schedule.
+type AmortRow struct {
+	Period    int              // period number starting from 1
+	Payment   fxd.FixedDecimal // Interest + Principal
+	Interest  fxd.FixedDecimal // interest accrued in this period
+	Principal fxd.FixedDecimal // principal repaid in this period
+	Balance   fxd.FixedDecimal // outstanding principal after payment
+}
+
+// Amortize returns schedule of a loan of principal repaid in nper
+// periods at periodic rate.
+// Returns fxd.DecErrInvalidValue if principal or rate is negative,
+// or nper is not positive.
+func (c *Context) Amortize(principal, rate fxd.FixedDecimal, nper int, method AmortMethod) ([]AmortRow, error) {
+	w := c.start()
+	w.check(principal, rate)
+	if principal.Sign() < 0 || rate.Sign() < 0 || nper <= 0 || method > AmortInterestOnly {
+		w.fail(fxd.DecErrInvalidValue)
+	}
+	balance := c.round(principal)
+	// fixed amount of each period, payment or principal
+	var fixed fxd.FixedDecimal
+	switch method {
+	case AmortFixedPayment:
+		if rate.IsZero() {
+			fixed = w.div(balance, num(int64(nper)))
+		} else {
+			qn := w.powInt(w.add(num(1), rate), int64(nper))
+			fixed = w.div(w.mul(balance, qn), w.annuity(rate, qn, DueEnd))
+		}
+	case AmortFixedPrincipal:
+		fixed = w.div(balance, num(int64(nper)))
+	}
+	if err := w.err(); err != nil {
+		return nil, err
+	}
+	fixed = c.round(fixed)
+	rows := make([]AmortRow, nper)
+	for i := range rows {
+		row := &rows[i]
+		row.Period = i + 1
+		row.Interest = c.round(w.mul(balance, rate))
+		switch {
+		case i == nper-1:
+			row.Principal = balance
+		case method == AmortFixedPayment:
+			row.Principal = w.sub(fixed, row.Interest)
+		case method == AmortFixedPrincipal:
+			row.Principal = fixed
+		default:
+			row.Principal = num(0)
+		}
+		// residue of rounded payments never repays more than balance
+		if row.Principal.Sign() < 0 {
+			row.Principal = num(0)
+		} else if balance.Less(&row.Principal) {
+			row.Principal = balance
+		}
+		row.Payment = w.add(row.Interest, row.Principal)
+		balance = w.sub(balance, row.Principal)
+		row.Balance = balance
+		if err := w.err(); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// round rounds value to scale of this context.
+func (c *Context) round(fd fxd.FixedDecimal) fxd.FixedDecimal {
+	fd, _ = c.result(newWork(), fd)
+	return fd
+}
@@ -0,0 +1,121 @@
+// Discounted cash flows
+//
+// NPV and IRR discount values of equally spaced periods, the first
+// value of NPV is at end of first period while the first value of IRR
+// is at time zero, same as Excel.
+// XIRR discounts each value by actual days from the first date in
+// a 365-day year.
+package fin
+
+import (
+	"time"
+
+	"github.com/jiangzhe/fxd"
+)
+
+// NPV returns net present value of values at end of each period.
+func (c *Context) NPV(rate fxd.FixedDecimal, values []fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(rate)
+	w.check(values...)
+	d := w.div(num(1), w.add(num(1), rate))
+	factor, sum := d, num(0)
+	for i := range values {
+		sum = w.add(sum, w.mul(values[i], factor))
+		factor = w.mul(factor, d)
+	}
+	return c.result(w, sum)
+}
+
+// IRR returns internal rate of return of values at each period,
+// where NPV of values from time zero is zero.
+// guess is the initial rate, nil means 10% as Excel.
+// values must contain at least one positive and one negative value.
+func (c *Context) IRR(values []fxd.FixedDecimal, guess *fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(values...)
+	if !mixedSigns(values) {
+		w.fail(fxd.DecErrInvalidValue)
+	}
+	if err := w.err(); err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	return c.solve(irrFunc(values), guessOrDefault(guess))
+}
+
+// irrFunc returns NPV of values from time zero and its derivative.
+func irrFunc(values []fxd.FixedDecimal) rateFunc {
+	return func(w *work, r fxd.FixedDecimal) (fxd.FixedDecimal, fxd.FixedDecimal) {
+		// f = sum(v[i]*d^i), f' = sum(-i*v[i]*d^(i+1)), where d = 1/(1+r)
+		d := w.div(num(1), w.add(num(1), r))
+		factor, f, df := num(1), num(0), num(0)
+		for i := range values {
+			term := w.mul(values[i], factor)
+			f = w.add(f, term)
+			df = w.sub(df, w.mul(w.mul(term, num(int64(i))), d))
+			factor = w.mul(factor, d)
+		}
+		return f, df
+	}
+}
+
+// XIRR returns internal rate of return of values at given dates.
+// Only calendar dates are used, time of day is ignored.
+// No date can be earlier than the first one.
+// guess is the initial rate, nil means 10% as Excel.
+func (c *Context) XIRR(values []fxd.FixedDecimal, dates []time.Time, guess *fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(values...)
+	if len(values) != len(dates) || !mixedSigns(values) {
+		w.fail(fxd.DecErrInvalidValue)
+	}
+	// exponent of each value, in years of 365 days
+	years := make([]fxd.FixedDecimal, len(dates))
+	for i := range dates {
+		days := civilDays(dates[i]) - civilDays(dates[0])
+		if days < 0 {
+			w.fail(fxd.DecErrInvalidValue)
+		}
+		years[i] = w.div(num(days), num(365))
+	}
+	if err := w.err(); err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	return c.solve(xirrFunc(values, years), guessOrDefault(guess))
+}
+
+// xirrFunc returns NPV of values at given years and its derivative.
+func xirrFunc(values, years []fxd.FixedDecimal) rateFunc {
+	return func(w *work, r fxd.FixedDecimal) (fxd.FixedDecimal, fxd.FixedDecimal) {
+		// f = sum(v[i]*(1+r)^-y[i]), f' = sum(-y[i]*v[i]*(1+r)^(-y[i]-1))
+		q := w.add(num(1), r)
+		lnq := w.ln(q)
+		f, df := num(0), num(0)
+		for i := range values {
+			term := w.mul(values[i], w.exp(w.sub(num(0), w.mul(years[i], lnq))))
+			f = w.add(f, term)
+			df = w.sub(df, w.div(w.mul(years[i], term), q))
+		}
+		return f, df
+	}
+}
+
+// mixedSigns returns true if values have both positive and negative ones.
+func mixedSigns(values []fxd.FixedDecimal) bool {
+	var pos, neg bool
+	for i := range values {
+		switch values[i].Sign() {
+		case 1:
+			pos = true
+		case -1:
+			neg = true
+		}
+	}
+	return pos && neg
+}
+
+// civilDays returns days since Unix epoch of the calendar date of t.
+func civilDays(t time.Time) int64 {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
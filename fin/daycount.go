@@ -0,0 +1,175 @@
+// Day-count conventions
+//
+// A day-count convention measures time between two dates as a
+// fraction of year, which is a ratio of integral day counts, e.g.
+// ACT/360 is actual days over 360. Ratio returns numerator and
+// denominator exactly, so accrued interest is divided only once,
+// and rounded once to scale of Context.
+// Only calendar dates are used, time of day is ignored.
+package fin
+
+import (
+	"time"
+
+	"github.com/jiangzhe/fxd"
+)
+
+// DayCount is day-count convention.
+type DayCount uint8
+
+const (
+	// DayCount30360US is 30/360 US (bond basis), with end of February
+	// adjustment: if start is last day of February, it is treated as
+	// 30th, so is end if it's also last day of February; end of 31st
+	// is treated as 30th if start is 30th or 31st; start of 31st is
+	// treated as 30th.
+	DayCount30360US DayCount = iota
+	// DayCount30E360 is 30E/360 (Eurobond basis), both 31st of start
+	// and end are treated as 30th.
+	DayCount30E360
+	// DayCountAct360 is actual days over 360.
+	DayCountAct360
+	// DayCountAct365F is actual days over 365, regardless of leap years.
+	DayCountAct365F
+	// DayCountActActISDA is actual days in leap years over 366, plus
+	// actual days in other years over 365.
+	DayCountActActISDA
+	// DayCountActActICMA is actual days over actual days of the coupon
+	// period times coupon frequency.
+	DayCountActActICMA
+)
+
+// CouponPeriod is the regular coupon period containing accrual dates,
+// required by DayCountActActICMA only.
+type CouponPeriod struct {
+	Start     time.Time // start date of the coupon period
+	End       time.Time // end date of the coupon period
+	Frequency int       // number of coupons per year
+}
+
+// Ratio returns year fraction from start to end as numerator over
+// denominator.
+// period must contain start and end for DayCountActActICMA, and is
+// ignored by other conventions.
+// Returns fxd.DecErrInvalidValue if end is earlier than start, or
+// period is invalid.
+func (dc DayCount) Ratio(start, end time.Time, period *CouponPeriod) (int64, int64, error) {
+	days := civilDays(end) - civilDays(start)
+	if days < 0 {
+		return 0, 0, fxd.DecErrInvalidValue
+	}
+	switch dc {
+	case DayCount30360US, DayCount30E360:
+		return days360(start, end, dc == DayCount30360US), 360, nil
+	case DayCountAct360:
+		return days, 360, nil
+	case DayCountAct365F:
+		return days, 365, nil
+	case DayCountActActISDA:
+		n, dn := actActISDA(start, end)
+		return n, dn, nil
+	case DayCountActActICMA:
+		if period == nil || period.Frequency <= 0 ||
+			civilDays(period.Start) >= civilDays(period.End) ||
+			civilDays(start) < civilDays(period.Start) ||
+			civilDays(end) > civilDays(period.End) {
+			return 0, 0, fxd.DecErrInvalidValue
+		}
+		return days, int64(period.Frequency) * (civilDays(period.End) - civilDays(period.Start)), nil
+	}
+	return 0, 0, fxd.DecErrInvalidValue
+}
+
+// YearFraction returns year fraction from start to end, rounded to
+// scale of this context.
+func (c *Context) YearFraction(dc DayCount, start, end time.Time, period *CouponPeriod) (fxd.FixedDecimal, error) {
+	w := c.start()
+	n, dn, err := dc.Ratio(start, end, period)
+	if err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	return c.result(w, w.div(num(n), num(dn)))
+}
+
+// AccruedInterest returns interest of face value at annual coupon
+// rate accrued from start to end, rounded to scale of this context
+// with mode.
+// Only fxd.DecRoundHalfUp is supported.
+func (c *Context) AccruedInterest(face, coupon fxd.FixedDecimal, dc DayCount, start, end time.Time, period *CouponPeriod, mode fxd.DecRoundMode) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(face, coupon)
+	if mode != fxd.DecRoundHalfUp {
+		w.fail(fxd.DecErrInvalidValue)
+	}
+	n, dn, err := dc.Ratio(start, end, period)
+	if err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	// face*coupon*n is exact, only the division is rounded
+	return c.result(w, w.div(w.mul(w.mul(face, coupon), num(n)), num(dn)))
+}
+
+// days360 returns days between dates in 30-day months.
+func days360(start, end time.Time, us bool) int64 {
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if us {
+		if lastOfFeb(y1, m1, d1) {
+			if lastOfFeb(y2, m2, d2) {
+				d2 = 30
+			}
+			d1 = 30
+		}
+		if d2 == 31 && d1 >= 30 {
+			d2 = 30
+		}
+		if d1 == 31 {
+			d1 = 30
+		}
+	} else {
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 {
+			d2 = 30
+		}
+	}
+	return int64(360*(y2-y1) + 30*(int(m2)-int(m1)) + d2 - d1)
+}
+
+func lastOfFeb(y int, m time.Month, d int) bool {
+	return m == time.February && d == 28+daysInYear(y)-365
+}
+
+// actActISDA returns sum of days in each calendar year over days of
+// that year, as numerator over 365*366 reduced.
+func actActISDA(start, end time.Time) (int64, int64) {
+	var n int64
+	from := civilDays(start)
+	for y := start.Year(); y <= end.Year(); y++ {
+		to := civilDays(time.Date(y+1, 1, 1, 0, 0, 0, 0, time.UTC))
+		if e := civilDays(end); e < to {
+			to = e
+		}
+		// days/365 = days*366/(365*366), and vice versa
+		n += (to - from) * int64(365+366-daysInYear(y))
+		from = to
+	}
+	den := int64(365 * 366)
+	g := gcd(n, den)
+	return n / g, den / g
+}
+
+func daysInYear(y int) int {
+	if y%4 == 0 && (y%100 != 0 || y%400 == 0) {
+		return 366
+	}
+	return 365
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
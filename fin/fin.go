@@ -0,0 +1,155 @@
+// Package fin implements spreadsheet-compatible financial functions
+// on fixed-point decimals.
+//
+// Functions follow the sign convention of Excel: cash paid out is
+// negative and cash received is positive, and they are methods of
+// Context, which decides precision of results and iteration limit
+// of the solvers.
+//
+// Intermediate values are computed at WorkFrac fractional digits,
+// and results are rounded half up to the scale of Context.
+package fin
+
+import (
+	"errors"
+
+	"github.com/jiangzhe/fxd"
+)
+
+const (
+	// WorkFrac is fractional digits of intermediate values.
+	WorkFrac = 24
+	// MaxScale is maximum scale of results, leaving guard digits
+	// for rounding.
+	MaxScale = WorkFrac - 4
+	// DefaultMaxIter is default iteration limit of solvers.
+	DefaultMaxIter = 100
+)
+
+// ErrNoConvergence is returned if a solver cannot find the root
+// within iteration limit.
+var ErrNoConvergence = errors.New("fin: no convergence")
+
+// Due is the timing of payments within each period.
+type Due uint8
+
+const (
+	DueEnd   Due = iota // payments at end of period, Excel type 0
+	DueBegin            // payments at beginning of period, Excel type 1
+)
+
+// Context configures financial functions.
+type Context struct {
+	Scale   int // fractional digits of results, rounded half up
+	MaxIter int // iteration limit of RATE, IRR and XIRR, 0 means DefaultMaxIter
+}
+
+// NewContext creates a context with given result scale and default
+// iteration limit.
+func NewContext(scale int) *Context {
+	return &Context{Scale: scale, MaxIter: DefaultMaxIter}
+}
+
+// start returns working arithmetic of this context, which fails with
+// fxd.DecErrInvalidValue if scale is out of range.
+func (c *Context) start() *work {
+	w := newWork()
+	if c.Scale < 0 || c.Scale > MaxScale {
+		w.fail(fxd.DecErrInvalidValue)
+	}
+	return w
+}
+
+func (c *Context) maxIter() int {
+	if c.MaxIter <= 0 {
+		return DefaultMaxIter
+	}
+	return c.MaxIter
+}
+
+// result rounds value to scale of this context.
+func (c *Context) result(w *work, fd fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	if err := w.err(); err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	fd.Round(c.Scale)
+	if fd.Sign() == 0 { // no negative zero
+		fd.Abs()
+	}
+	return fd, nil
+}
+
+// work is arithmetic at WorkFrac fractional digits on fxd.Calculator,
+// which records the first error and skips all following operations.
+// Once an error is recorded, every operation returns zero, so a formula
+// can be written without checking each step, but err must be checked
+// before a result is returned, e.g. after each row of a schedule.
+type work struct {
+	calc fxd.Calculator
+}
+
+// newWork creates working arithmetic, which divides with one more digit
+// than WorkFrac, so rounding of the truncated quotient is correct.
+func newWork() *work {
+	w := &work{}
+	w.calc.SetDivIncrFrac(WorkFrac + 1)
+	return w
+}
+
+// err returns the first error.
+func (w *work) err() error {
+	return w.calc.Err()
+}
+
+// fail records err if no error is recorded.
+func (w *work) fail(err error) {
+	w.calc.Fail(err)
+}
+
+// check records fxd.DecErrInvalidValue if any value is NaN or Inf.
+func (w *work) check(fds ...fxd.FixedDecimal) {
+	for i := range fds {
+		w.calc.Set(fds[i])
+	}
+}
+
+func (w *work) add(lhs, rhs fxd.FixedDecimal) fxd.FixedDecimal {
+	return w.apply(w.calc.Add, lhs, rhs)
+}
+
+func (w *work) sub(lhs, rhs fxd.FixedDecimal) fxd.FixedDecimal {
+	return w.apply(w.calc.Sub, lhs, rhs)
+}
+
+func (w *work) mul(lhs, rhs fxd.FixedDecimal) fxd.FixedDecimal {
+	return w.apply(w.calc.Mul, lhs, rhs)
+}
+
+func (w *work) div(lhs, rhs fxd.FixedDecimal) fxd.FixedDecimal {
+	return w.apply(w.calc.Div, lhs, rhs)
+}
+
+func (w *work) apply(op func(...fxd.FixedDecimal) *fxd.Calculator, lhs, rhs fxd.FixedDecimal) fxd.FixedDecimal {
+	w.calc.Set(lhs)
+	res, err := op(rhs).Result()
+	if err != nil {
+		return fxd.FixedDecimal{}
+	}
+	if int(res.Frac()) > WorkFrac {
+		res.Round(WorkFrac)
+	}
+	return res
+}
+
+func num(v int64) fxd.FixedDecimal {
+	return fxd.DecimalFromInt64(v)
+}
+
+// mustDecimal returns fd, or panics on error. It's used by constants
+// of this package, which are always valid.
+func mustDecimal(fd fxd.FixedDecimal, err error) fxd.FixedDecimal {
+	if err != nil {
+		panic(err)
+	}
+	return fd
+}
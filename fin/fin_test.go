@@ -0,0 +1,402 @@
+package fin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jiangzhe/fxd"
+)
+
+func d(s string) fxd.FixedDecimal {
+	fd, err := fxd.DecimalFromAsciiString(s)
+	if err != nil {
+		panic(err)
+	}
+	return fd
+}
+
+func ds(ss ...string) []fxd.FixedDecimal {
+	fds := make([]fxd.FixedDecimal, len(ss))
+	for i, s := range ss {
+		fds[i] = d(s)
+	}
+	return fds
+}
+
+func date(y int, m time.Month, day int) time.Time {
+	return time.Date(y, m, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Expected values are Excel results, with more digits than the
+// documentation which shows rounded values only.
+func TestTVM(t *testing.T) {
+	c := NewContext(10)
+	monthly8 := d("0.006666666666666666666667") // 8%/12
+	monthly6 := d("0.005")
+	type tcase struct {
+		name     string
+		fn       func() (fxd.FixedDecimal, error)
+		expected string
+	}
+	for _, tc := range []tcase{
+		{"PV(8%/12,240,500)", func() (fxd.FixedDecimal, error) {
+			return c.PV(monthly8, d("240"), d("500"), d("0"), DueEnd)
+		}, "-59777.1458511880"},
+		{"PV(8%/12,240,500,0,1)", func() (fxd.FixedDecimal, error) {
+			return c.PV(monthly8, d("240"), d("500"), d("0"), DueBegin)
+		}, "-60175.6601568626"},
+		{"PV(5%,2.5,-100)", func() (fxd.FixedDecimal, error) {
+			return c.PV(d("0.05"), d("2.5"), d("-100"), d("0"), DueEnd)
+		}, "229.6597316126"},
+		{"PV(0,10,-100)", func() (fxd.FixedDecimal, error) {
+			return c.PV(d("0"), d("10"), d("-100"), d("0"), DueEnd)
+		}, "1000.0000000000"},
+		{"FV(6%/12,10,-200,-500,1)", func() (fxd.FixedDecimal, error) {
+			return c.FV(monthly6, d("10"), d("-200"), d("-500"), DueBegin)
+		}, "2581.4033740602"},
+		{"FV(1%,12,-1000)", func() (fxd.FixedDecimal, error) {
+			return c.FV(d("0.01"), d("12"), d("-1000"), d("0"), DueEnd)
+		}, "12682.5030131970"},
+		{"PMT(8%/12,10,10000)", func() (fxd.FixedDecimal, error) {
+			return c.PMT(monthly8, d("10"), d("10000"), d("0"), DueEnd)
+		}, "-1037.0320893592"},
+		{"PMT(6%/12,216,0,50000)", func() (fxd.FixedDecimal, error) {
+			return c.PMT(monthly6, d("216"), d("0"), d("50000"), DueEnd)
+		}, "-129.0811608680"},
+		{"PMT(0,10,1000)", func() (fxd.FixedDecimal, error) {
+			return c.PMT(d("0"), d("10"), d("1000"), d("0"), DueEnd)
+		}, "-100.0000000000"},
+		{"NPER(1%,-100,-1000,10000,1)", func() (fxd.FixedDecimal, error) {
+			return c.NPER(d("0.01"), d("-100"), d("-1000"), d("10000"), DueBegin)
+		}, "59.6738656743"},
+		{"NPER(1%,-100,-1000,10000)", func() (fxd.FixedDecimal, error) {
+			return c.NPER(d("0.01"), d("-100"), d("-1000"), d("10000"), DueEnd)
+		}, "60.0821228538"},
+		{"NPER(1%,-100,-1000)", func() (fxd.FixedDecimal, error) {
+			return c.NPER(d("0.01"), d("-100"), d("-1000"), d("0"), DueEnd)
+		}, "-9.5785940398"},
+		{"NPER(0,-100,1000)", func() (fxd.FixedDecimal, error) {
+			return c.NPER(d("0"), d("-100"), d("1000"), d("0"), DueEnd)
+		}, "10.0000000000"},
+		{"RATE(48,-200,8000)", func() (fxd.FixedDecimal, error) {
+			return c.RATE(d("48"), d("-200"), d("8000"), d("0"), DueEnd, nil)
+		}, "0.0077014725"},
+		{"RATE(10,-100,1000)", func() (fxd.FixedDecimal, error) {
+			return c.RATE(d("10"), d("-100"), d("1000"), d("0"), DueEnd, nil)
+		}, "0.0000000000"},
+	} {
+		res, err := tc.fn()
+		if err != nil || res.ToString(-1) != tc.expected {
+			t.Fatalf("%v mismatch: actual=%v,%v, expected=%v", tc.name, res.ToString(-1), err, tc.expected)
+		}
+	}
+	// result scale as shown in Excel
+	c = NewContext(2)
+	if res, err := c.PV(monthly8, d("240"), d("500"), d("0"), DueEnd); err != nil || res.ToString(-1) != "-59777.15" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+}
+
+func TestCashFlow(t *testing.T) {
+	c := NewContext(10)
+	minus10 := d("-0.1")
+	type tcase struct {
+		name     string
+		fn       func() (fxd.FixedDecimal, error)
+		expected string
+	}
+	for _, tc := range []tcase{
+		{"NPV(10%,-10000,3000,4200,6800)", func() (fxd.FixedDecimal, error) {
+			return c.NPV(d("0.1"), ds("-10000", "3000", "4200", "6800"))
+		}, "1188.4434123352"},
+		{"NPV(8%,8000,9200,10000,12000,14500)-40000", func() (fxd.FixedDecimal, error) {
+			res, err := c.NPV(d("0.08"), ds("8000", "9200", "10000", "12000", "14500"))
+			return res.MustSub(d("40000")), err
+		}, "1922.0615549324"},
+		{"IRR(-70000,12000,15000,18000,21000,26000)", func() (fxd.FixedDecimal, error) {
+			return c.IRR(ds("-70000", "12000", "15000", "18000", "21000", "26000"), nil)
+		}, "0.0866309480"},
+		{"IRR(-70000,12000,15000,18000,21000)", func() (fxd.FixedDecimal, error) {
+			return c.IRR(ds("-70000", "12000", "15000", "18000", "21000"), nil)
+		}, "-0.0212448483"},
+		{"IRR(-70000,12000,15000,-10%)", func() (fxd.FixedDecimal, error) {
+			return c.IRR(ds("-70000", "12000", "15000"), &minus10)
+		}, "-0.4435069413"},
+		{"XIRR", func() (fxd.FixedDecimal, error) {
+			return c.XIRR(ds("-10000", "2750", "4250", "3250", "2750"), []time.Time{
+				date(2008, 1, 1), date(2008, 3, 1), date(2008, 10, 30), date(2009, 2, 15), date(2009, 4, 1),
+			}, nil)
+		}, "0.3733625335"},
+	} {
+		res, err := tc.fn()
+		if err != nil || res.ToString(-1) != tc.expected {
+			t.Fatalf("%v mismatch: actual=%v,%v, expected=%v", tc.name, res.ToString(-1), err, tc.expected)
+		}
+	}
+}
+
+func TestSolver(t *testing.T) {
+	c := NewContext(10)
+	values := ds("-70000", "12000", "15000", "18000", "21000", "26000")
+	tol := c.tolerance(newWork())
+	for _, scale := range []int{0, 7, 16, MaxScale} {
+		expected := d("0." + strings.Repeat("0", scale+1) + "1")
+		if actual := NewContext(scale).tolerance(newWork()); actual.Compare(&expected) != 0 {
+			t.Fatalf("tolerance %v mismatch: actual=%v, expected=%v", scale, actual.ToString(-1), expected.ToString(-1))
+		}
+	}
+	// bisection alone finds the same root
+	r, err := c.bisect(irrFunc(values), d("0.1"), &tol)
+	if err != nil {
+		t.Fatalf("failed %v", err)
+	}
+	if res, _ := c.result(newWork(), r); res.ToString(-1) != "0.0866309480" {
+		t.Fatalf("failed %v", res.ToString(-1))
+	}
+	// Newton from a far guess falls back to bisection
+	far := d("5")
+	if res, err := c.IRR(values, &far); err != nil || res.ToString(-1) != "0.0866309480" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	c.MaxIter = 2
+	if _, err := c.IRR(values, nil); err != ErrNoConvergence {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestFinError(t *testing.T) {
+	c := NewContext(10)
+	if _, err := c.NPER(d("0.01"), d("-5"), d("1000"), d("0"), DueEnd); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := c.PV(d("-1"), d("10"), d("-100"), d("0"), DueEnd); err != fxd.DecErrDivisionByZero {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := c.IRR(ds("100", "200"), nil); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	dates := []time.Time{date(2020, 1, 1), date(2019, 12, 31)}
+	if _, err := c.XIRR(ds("-100", "110"), dates, nil); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := c.XIRR(ds("-100", "110", "1"), dates, nil); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	nan, _ := fxd.DecimalFromAsciiString("NaN")
+	if _, err := c.FV(nan, d("10"), d("-100"), d("0"), DueEnd); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	minus2 := d("-2")
+	if _, err := c.RATE(d("10"), d("-100"), d("1000"), d("0"), DueEnd, &minus2); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	c.Scale = MaxScale + 1
+	if _, err := c.PMT(d("0.01"), d("10"), d("1000"), d("0"), DueEnd); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := c.RATE(d("10"), d("-100"), d("1000"), d("0"), DueEnd, nil); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestElementary(t *testing.T) {
+	type tcase struct {
+		fn       string
+		x        string
+		expected string
+	}
+	// expected values rounded to 18 digits, absolute error of
+	// large values grows with magnitude
+	for _, tc := range []tcase{
+		{"exp", "1", "2.718281828459045235"},
+		{"exp", "-1", "0.367879441171442322"},
+		{"exp", "10.5", "36315.502674246637738912"},
+		{"exp", "0", "1.000000000000000000"},
+		{"exp", "-100", "0.000000000000000000"},
+		{"ln", "2", "0.693147180559945309"},
+		{"ln", "0.001", "-6.907755278982137052"},
+		{"ln", "1", "0.000000000000000000"},
+		{"ln", "123456789", "18.631401766168018033"},
+		{"ln", "100000000000000000000000000000000000", "80.590478254791598941"},
+		{"ln", "12345678901234567890.5", "43.959837789202520557"},
+	} {
+		w := newWork()
+		var res fxd.FixedDecimal
+		if tc.fn == "exp" {
+			res = w.exp(d(tc.x))
+		} else {
+			res = w.ln(d(tc.x))
+		}
+		res.Round(18)
+		if w.err() != nil || res.ToString(-1) != tc.expected {
+			t.Fatalf("%v(%v) mismatch: actual=%v,%v, expected=%v", tc.fn, tc.x, res.ToString(-1), w.err(), tc.expected)
+		}
+	}
+	w := newWork()
+	if w.exp(d("151")); w.err() != fxd.DecErrOverflow {
+		t.Fatalf("failed %v", w.err())
+	}
+	w = newWork()
+	if w.ln(d("0")); w.err() != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", w.err())
+	}
+}
+
+func TestAmortize(t *testing.T) {
+	c := NewContext(2)
+	type tcase struct {
+		principal, rate string
+		nper            int
+		method          AmortMethod
+		expected        []string // payment interest principal balance
+	}
+	for _, tc := range []tcase{
+		{"10000", "0.01", 12, AmortFixedPayment, []string{
+			"888.49 100.00 788.49 9211.51",
+			"888.49 92.12 796.37 8415.14",
+			"888.49 84.15 804.34 7610.80",
+			"888.49 76.11 812.38 6798.42",
+			"888.49 67.98 820.51 5977.91",
+			"888.49 59.78 828.71 5149.20",
+			"888.49 51.49 837.00 4312.20",
+			"888.49 43.12 845.37 3466.83",
+			"888.49 34.67 853.82 2613.01",
+			"888.49 26.13 862.36 1750.65",
+			"888.49 17.51 870.98 879.67",
+			"888.47 8.80 879.67 0.00",
+		}},
+		{"10000", "0.01", 12, AmortFixedPrincipal, []string{
+			"933.33 100.00 833.33 9166.67",
+			"925.00 91.67 833.33 8333.34",
+			"916.66 83.33 833.33 7500.01",
+			"908.33 75.00 833.33 6666.68",
+			"900.00 66.67 833.33 5833.35",
+			"891.66 58.33 833.33 5000.02",
+			"883.33 50.00 833.33 4166.69",
+			"875.00 41.67 833.33 3333.36",
+			"866.66 33.33 833.33 2500.03",
+			"858.33 25.00 833.33 1666.70",
+			"850.00 16.67 833.33 833.37",
+			"841.70 8.33 833.37 0.00",
+		}},
+		{"10000", "0.01", 3, AmortInterestOnly, []string{
+			"100.00 100.00 0.00 10000.00",
+			"100.00 100.00 0.00 10000.00",
+			"10100.00 100.00 10000.00 0.00",
+		}},
+		{"1000", "0", 3, AmortFixedPayment, []string{
+			"333.33 0.00 333.33 666.67",
+			"333.33 0.00 333.33 333.34",
+			"333.34 0.00 333.34 0.00",
+		}},
+		{"0.01", "0.005", 1, AmortFixedPrincipal, []string{
+			"0.01 0.00 0.01 0.00",
+		}},
+	} {
+		rows, err := c.Amortize(d(tc.principal), d(tc.rate), tc.nper, tc.method)
+		if err != nil || len(rows) != len(tc.expected) {
+			t.Fatalf("amortize %v %v mismatch: actual=%v,%v, expected=%v rows", tc.principal, tc.rate, len(rows), err, len(tc.expected))
+		}
+		for i, row := range rows {
+			actual := row.Payment.ToString(2) + " " + row.Interest.ToString(2) + " " + row.Principal.ToString(2) + " " + row.Balance.ToString(2)
+			if row.Period != i+1 || actual != tc.expected[i] {
+				t.Fatalf("row %v mismatch: actual=%v, expected=%v", row.Period, actual, tc.expected[i])
+			}
+		}
+	}
+	// residue of rounded payment never overpays balance
+	rows, err := c.Amortize(d("1"), d("0.001"), 300, AmortFixedPayment)
+	if err != nil || !rows[len(rows)-1].Balance.IsZero() {
+		t.Fatalf("failed %v", err)
+	}
+	for _, row := range rows {
+		if row.Principal.Sign() < 0 || row.Balance.Sign() < 0 {
+			t.Fatalf("row %v mismatch: principal=%v, balance=%v", row.Period, row.Principal.ToString(-1), row.Balance.ToString(-1))
+		}
+	}
+	for _, args := range [][2]string{{"-100", "0.01"}, {"100", "-0.01"}} {
+		if _, err := c.Amortize(d(args[0]), d(args[1]), 12, AmortFixedPayment); err != fxd.DecErrInvalidValue {
+			t.Fatalf("failed %v", err)
+		}
+	}
+	if _, err := c.Amortize(d("100"), d("0.01"), 0, AmortFixedPayment); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDayCount(t *testing.T) {
+	type tcase struct {
+		dc         DayCount
+		start, end time.Time
+		num, den   int64
+	}
+	for _, tc := range []tcase{
+		{DayCount30360US, date(2007, 2, 28), date(2007, 3, 31), 30, 360},
+		{DayCount30E360, date(2007, 2, 28), date(2007, 3, 31), 32, 360},
+		{DayCount30360US, date(2008, 2, 29), date(2009, 2, 28), 360, 360},
+		{DayCount30E360, date(2008, 2, 29), date(2009, 2, 28), 359, 360},
+		{DayCount30360US, date(2007, 1, 31), date(2007, 2, 28), 28, 360},
+		{DayCount30360US, date(2007, 1, 30), date(2007, 3, 31), 60, 360},
+		{DayCount30360US, date(2007, 1, 15), date(2007, 3, 31), 76, 360},
+		{DayCount30E360, date(2007, 1, 15), date(2007, 3, 31), 75, 360},
+		{DayCountAct360, date(2007, 1, 15), date(2007, 7, 15), 181, 360},
+		{DayCountAct365F, date(2008, 1, 15), date(2008, 7, 15), 182, 365},
+		{DayCountActActISDA, date(2003, 11, 1), date(2004, 5, 1), 61*366 + 121*365, 365 * 366},
+		{DayCountActActISDA, date(2004, 1, 1), date(2005, 1, 1), 1, 1},
+		{DayCountActActISDA, date(2004, 3, 1), date(2004, 3, 1), 0, 1},
+	} {
+		num, den, err := tc.dc.Ratio(tc.start, tc.end, nil)
+		if err != nil || num*tc.den != tc.num*den {
+			t.Fatalf("day count %v from %v mismatch: actual=%v/%v,%v, expected=%v/%v", tc.dc, tc.start, num, den, err, tc.num, tc.den)
+		}
+	}
+	c := NewContext(10)
+	period := &CouponPeriod{date(2003, 11, 1), date(2004, 5, 1), 2}
+	type ycase struct {
+		dc       DayCount
+		end      time.Time
+		expected string
+	}
+	for _, tc := range []ycase{
+		{DayCountActActISDA, date(2004, 5, 1), "0.4977243806"},
+		{DayCountActActISDA, date(2005, 3, 1), "1.3287671233"},
+		{DayCountActActICMA, date(2004, 5, 1), "0.5000000000"},
+		{DayCountActActICMA, date(2004, 1, 31), "0.2500000000"},
+		{DayCount30360US, date(2003, 12, 1), "0.0833333333"},
+	} {
+		res, err := c.YearFraction(tc.dc, date(2003, 11, 1), tc.end, period)
+		if err != nil || res.ToString(-1) != tc.expected {
+			t.Fatalf("year fraction %v to %v mismatch: actual=%v,%v, expected=%v", tc.dc, tc.end, res.ToString(-1), err, tc.expected)
+		}
+	}
+	c = NewContext(2)
+	type acase struct {
+		face, coupon string
+		dc           DayCount
+		start, end   time.Time
+		expected     string
+	}
+	for _, tc := range []acase{
+		{"1000000", "0.05", DayCountAct360, date(2007, 1, 15), date(2007, 7, 15), "25138.89"},
+		{"1000000", "0.05", DayCountActActISDA, date(2003, 11, 1), date(2004, 5, 1), "24886.22"},
+		{"100", "0.05", DayCountActActICMA, date(2003, 11, 1), date(2004, 1, 31), "1.25"},
+		{"100", "0.05", DayCountAct365F, date(2004, 1, 31), date(2004, 1, 31), "0.00"},
+	} {
+		res, err := c.AccruedInterest(d(tc.face), d(tc.coupon), tc.dc, tc.start, tc.end, period, fxd.DecRoundHalfUp)
+		if err != nil || res.ToString(-1) != tc.expected {
+			t.Fatalf("accrued interest %v mismatch: actual=%v,%v, expected=%v", tc.dc, res.ToString(-1), err, tc.expected)
+		}
+	}
+	for _, p := range []*CouponPeriod{nil, {date(2003, 11, 1), date(2004, 5, 1), 0}, {date(2003, 12, 1), date(2004, 5, 1), 2}} {
+		if _, _, err := DayCountActActICMA.Ratio(date(2003, 11, 1), date(2004, 1, 31), p); err != fxd.DecErrInvalidValue {
+			t.Fatalf("failed %v", err)
+		}
+	}
+	if _, _, err := DayCountAct360.Ratio(date(2004, 1, 31), date(2004, 1, 30), nil); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := c.AccruedInterest(d("100"), d("0.05"), DayCountAct360, date(2004, 1, 1), date(2004, 2, 1), nil, fxd.DecRoundMode(1)); err != fxd.DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Elementary functions at working precision
+//
+// Excel functions with fractional exponent, e.g. XIRR and NPER,
+// need exp and ln, which fxd does not provide.
+// exp uses Taylor series of the fractional part and powers of e for
+// the integral part. ln starts from float64 logarithm and refines it
+// with Halley iteration on exp, which triples correct digits each step.
+package fin
+
+import (
+	"math"
+
+	"github.com/jiangzhe/fxd"
+)
+
+// e rounded to MaxFrac digits.
+var decE = mustDecimal(fxd.DecimalFromAsciiString("2.718281828459045235360287471353"))
+
+const (
+	// maxExp is the largest integral part of exp argument, e^150 has 66 digits.
+	maxExp = 150
+	// minExp is the smallest integral part of exp argument with non-zero
+	// result at WorkFrac, e^-56 is less than 10^-24.
+	minExp = -56
+	// lnIter is iteration limit of ln refinement.
+	lnIter = 8
+	// floatIntg bounds integral part of float64 conversion.
+	floatIntg = 1e18
+	// floatFrac is fractional digits of float64 conversion, and
+	// floatScale is 10^floatFrac.
+	floatFrac  = 15
+	floatScale = 1e15
+)
+
+// powInt returns x^n.
+func (w *work) powInt(x fxd.FixedDecimal, n int64) fxd.FixedDecimal {
+	neg := n < 0
+	if neg {
+		n = -n
+	}
+	res := num(1)
+	for n > 0 && w.err() == nil {
+		if n&1 == 1 {
+			res = w.mul(res, x)
+		}
+		n >>= 1
+		if n > 0 {
+			x = w.mul(x, x)
+		}
+	}
+	if neg {
+		return w.div(num(1), res)
+	}
+	return res
+}
+
+// pow returns x^y, x must be positive if y is not an integer.
+func (w *work) pow(x, y fxd.FixedDecimal) fxd.FixedDecimal {
+	if n, ok := toInt64(y); ok {
+		return w.powInt(x, n)
+	}
+	return w.exp(w.mul(y, w.ln(x)))
+}
+
+// exp returns e^x.
+func (w *work) exp(x fxd.FixedDecimal) fxd.FixedDecimal {
+	if w.err() != nil {
+		return x
+	}
+	k := x
+	k.Floor()
+	n, ok := toInt64(k)
+	if !ok || n > maxExp {
+		w.fail(fxd.DecErrOverflow)
+		return x
+	}
+	if n < minExp {
+		return num(0)
+	}
+	f := w.sub(x, k) // in [0, 1)
+	sum, term := num(1), num(1)
+	for i := int64(1); w.err() == nil; i++ {
+		term = w.div(w.mul(term, f), num(i))
+		if term.IsZero() {
+			break
+		}
+		sum = w.add(sum, term)
+	}
+	return w.mul(w.powInt(decE, n), sum)
+}
+
+// ln returns natural logarithm of positive x.
+func (w *work) ln(x fxd.FixedDecimal) fxd.FixedDecimal {
+	if w.err() != nil {
+		return x
+	}
+	if x.Sign() <= 0 {
+		w.fail(fxd.DecErrInvalidValue)
+		return x
+	}
+	y := fromFloat(math.Log(toFloat(x)))
+	for i := 0; i < lnIter && w.err() == nil; i++ {
+		// y += 2*(x-e^y)/(x+e^y)
+		ey := w.exp(y)
+		delta := w.div(w.mul(num(2), w.sub(x, ey)), w.add(x, ey))
+		if delta.IsZero() {
+			break
+		}
+		y = w.add(y, delta)
+	}
+	return y
+}
+
+// toInt64 converts integral decimal to int64.
+func toInt64(fd fxd.FixedDecimal) (int64, bool) {
+	if !fd.IsInteger() || fd.CompareInt64(math.MinInt64) < 0 || fd.CompareInt64(math.MaxInt64) > 0 {
+		return 0, false
+	}
+	return fd.ToInt(), true
+}
+
+// toFloat converts positive decimal to float64 approximately.
+// The decimal is scaled by powers of Unit into [1, 10^18), so both
+// integral part and fractional digits of the mantissa fit int64.
+func toFloat(fd fxd.FixedDecimal) float64 {
+	scale := 1.0
+	for fd.CompareInt64(floatIntg) >= 0 {
+		fd, _ = fd.DivInt64(fxd.Unit)
+		scale *= fxd.Unit
+	}
+	for fd.CompareInt64(1) < 0 {
+		fd, _ = fd.MulInt64(fxd.Unit)
+		scale /= fxd.Unit
+	}
+	var intg, frac fxd.FixedDecimal
+	fd.ModfTo(&intg, &frac)
+	frac, _ = frac.MulInt64(floatIntg)
+	return (float64(intg.ToInt()) + float64(frac.ToInt())/floatIntg) * scale
+}
+
+// fromFloat converts float64 of small magnitude to decimal with
+// floatFrac fractional digits.
+func fromFloat(f float64) fxd.FixedDecimal {
+	var res fxd.FixedDecimal
+	n := num(int64(math.Round(f * floatScale)))
+	fxd.DecimalDivInt64(&n, floatScale, &res, floatFrac)
+	return res
+}
@@ -0,0 +1,153 @@
+// Root finding of rate equations
+//
+// RATE, IRR and XIRR find a rate where a function of it is zero.
+// Newton iteration starts from the guess, as Excel does, and converges
+// quickly near the root. If it fails, e.g. the derivative vanishes,
+// the rate goes below -100% or the limit is reached, a bracket with
+// sign change is searched outward from the guess, and then bisected.
+// Both stop once the rate changes less than 10^-(Scale+2).
+package fin
+
+import "github.com/jiangzhe/fxd"
+
+// rateFunc evaluates function and its derivative at rate r.
+type rateFunc func(w *work, r fxd.FixedDecimal) (fxd.FixedDecimal, fxd.FixedDecimal)
+
+// decTenth is 0.1, default guess of Excel and initial step of bracket search.
+var decTenth = mustDecimal(num(1).DivInt64(10))
+
+func guessOrDefault(guess *fxd.FixedDecimal) fxd.FixedDecimal {
+	if guess == nil {
+		return decTenth
+	}
+	return *guess
+}
+
+// solve finds root of f, rounded to scale of this context.
+func (c *Context) solve(f rateFunc, guess fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	if guess.IsNaN() || guess.IsInf() || guess.CompareInt64(-1) <= 0 {
+		return fxd.FixedDecimal{}, fxd.DecErrInvalidValue
+	}
+	w := newWork()
+	tol := c.tolerance(w)
+	if r, ok := c.newton(f, guess, &tol); ok {
+		return c.result(w, r)
+	}
+	r, err := c.bisect(f, guess, &tol)
+	if err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	return c.result(w, r)
+}
+
+// newton returns false if iteration fails.
+func (c *Context) newton(f rateFunc, r fxd.FixedDecimal, tol *fxd.FixedDecimal) (fxd.FixedDecimal, bool) {
+	for i := 0; i < c.maxIter(); i++ {
+		w := newWork()
+		fr, dfr := f(w, r)
+		if w.err() != nil || dfr.IsZero() {
+			return r, false
+		}
+		if fr.IsZero() {
+			return r, true
+		}
+		step := w.div(fr, dfr)
+		r = w.sub(r, step)
+		if w.err() != nil || r.CompareInt64(-1) <= 0 {
+			return r, false
+		}
+		step.Abs()
+		if step.Less(tol) {
+			return r, true
+		}
+	}
+	return r, false
+}
+
+// bisect searches a bracket outward from guess and bisects it.
+func (c *Context) bisect(f rateFunc, guess fxd.FixedDecimal, tol *fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	eval := func(r fxd.FixedDecimal) (int, bool) {
+		w := newWork()
+		fr, _ := f(w, r)
+		return fr.Sign(), w.err() == nil
+	}
+	lo, hi := guess, guess
+	slo, ok := eval(guess)
+	if !ok {
+		return fxd.FixedDecimal{}, ErrNoConvergence
+	}
+	if slo == 0 {
+		return guess, nil
+	}
+	shi := slo
+	w := newWork()
+	step := decTenth
+	minusOne := num(-1)
+	for i := 0; slo == shi; i++ {
+		if i == c.maxIter() {
+			return fxd.FixedDecimal{}, ErrNoConvergence
+		}
+		// lower end approaches -1 by halving the distance
+		l := w.sub(lo, step)
+		if l.CompareInt64(-1) <= 0 {
+			l = w.div(w.add(lo, minusOne), num(2))
+		}
+		if s, ok := eval(l); ok {
+			lo, slo = l, s
+		}
+		if slo == shi {
+			h := w.add(hi, step)
+			if s, ok := eval(h); ok {
+				hi, shi = h, s
+			}
+		}
+		step = w.mul(step, num(2))
+		if w.err() != nil {
+			return fxd.FixedDecimal{}, ErrNoConvergence
+		}
+	}
+	if slo == 0 {
+		return lo, nil
+	}
+	if shi == 0 {
+		return hi, nil
+	}
+	for i := 0; i < c.maxIter(); i++ {
+		mid := w.div(w.add(lo, hi), num(2))
+		s, ok := eval(mid)
+		if !ok || w.err() != nil {
+			return fxd.FixedDecimal{}, ErrNoConvergence
+		}
+		if s == 0 {
+			return mid, nil
+		}
+		if s == slo {
+			lo = mid
+		} else {
+			hi = mid
+		}
+		if width := w.sub(hi, lo); width.Less(tol) {
+			return w.div(w.add(lo, hi), num(2)), nil
+		}
+	}
+	return fxd.FixedDecimal{}, ErrNoConvergence
+}
+
+// tolerance returns 10^-(Scale+2).
+func (c *Context) tolerance(w *work) fxd.FixedDecimal {
+	tol := num(1)
+	// divisor of each step has at most DigitsPerUnit zeros, so it fits int64
+	for n := c.Scale + 2; n > 0; n -= fxd.DigitsPerUnit {
+		k, div := n, int64(1)
+		if k > fxd.DigitsPerUnit {
+			k = fxd.DigitsPerUnit
+		}
+		for i := 0; i < k; i++ {
+			div *= 10
+		}
+		if err := fxd.DecimalDivInt64(&tol, div, &tol, k); err != nil {
+			w.fail(err)
+		}
+	}
+	return tol
+}
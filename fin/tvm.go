@@ -0,0 +1,107 @@
+// Time value of money
+//
+// PV, FV, PMT, NPER and RATE solve the annuity equation of Excel:
+//
+//	pv*(1+rate)^nper + pmt*(1+rate*type)*((1+rate)^nper-1)/rate + fv = 0
+//
+// which becomes pv + pmt*nper + fv = 0 if rate is zero.
+package fin
+
+import "github.com/jiangzhe/fxd"
+
+// PV returns present value of a series of equal payments.
+func (c *Context) PV(rate, nper, pmt, fv fxd.FixedDecimal, due Due) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(rate, nper, pmt, fv)
+	if rate.IsZero() {
+		return c.result(w, w.sub(num(0), w.add(fv, w.mul(pmt, nper))))
+	}
+	qn := w.pow(w.add(num(1), rate), nper)
+	return c.result(w, w.div(w.sub(num(0), w.add(fv, w.mul(pmt, w.annuity(rate, qn, due)))), qn))
+}
+
+// FV returns future value of an investment with equal payments.
+func (c *Context) FV(rate, nper, pmt, pv fxd.FixedDecimal, due Due) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(rate, nper, pmt, pv)
+	if rate.IsZero() {
+		return c.result(w, w.sub(num(0), w.add(pv, w.mul(pmt, nper))))
+	}
+	qn := w.pow(w.add(num(1), rate), nper)
+	return c.result(w, w.sub(num(0), w.add(w.mul(pv, qn), w.mul(pmt, w.annuity(rate, qn, due)))))
+}
+
+// PMT returns payment of each period of a loan or investment.
+func (c *Context) PMT(rate, nper, pv, fv fxd.FixedDecimal, due Due) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(rate, nper, pv, fv)
+	if rate.IsZero() {
+		return c.result(w, w.div(w.sub(num(0), w.add(pv, fv)), nper))
+	}
+	qn := w.pow(w.add(num(1), rate), nper)
+	return c.result(w, w.div(w.sub(num(0), w.add(fv, w.mul(pv, qn))), w.annuity(rate, qn, due)))
+}
+
+// NPER returns number of periods of a loan or investment.
+// Returns fxd.DecErrInvalidValue if no number of periods satisfies
+// the equation.
+func (c *Context) NPER(rate, pmt, pv, fv fxd.FixedDecimal, due Due) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(rate, pmt, pv, fv)
+	if rate.IsZero() {
+		return c.result(w, w.div(w.sub(num(0), w.add(pv, fv)), pmt))
+	}
+	// nper = ln((a-fv*rate)/(a+pv*rate)) / ln(1+rate), where a = pmt*(1+rate*type)
+	a := w.mul(pmt, w.dueFactor(rate, due))
+	x := w.div(w.sub(a, w.mul(fv, rate)), w.add(a, w.mul(pv, rate)))
+	return c.result(w, w.div(w.ln(x), w.ln(w.add(num(1), rate))))
+}
+
+// RATE returns interest rate per period of an annuity.
+// guess is the initial rate of Newton iteration, nil means 10% as Excel.
+// If Newton iteration fails, the rate is searched by bisection.
+func (c *Context) RATE(nper, pmt, pv, fv fxd.FixedDecimal, due Due, guess *fxd.FixedDecimal) (fxd.FixedDecimal, error) {
+	w := c.start()
+	w.check(nper, pmt, pv, fv)
+	if err := w.err(); err != nil {
+		return fxd.FixedDecimal{}, err
+	}
+	return c.solve(func(w *work, r fxd.FixedDecimal) (fxd.FixedDecimal, fxd.FixedDecimal) {
+		if r.IsZero() {
+			// f = pv+pmt*n+fv, f' = n*pv + pmt*n*(n-1)/2 + pmt*type*n
+			f := w.add(w.add(pv, w.mul(pmt, nper)), fv)
+			df := w.mul(nper, w.add(pv, w.mul(pmt, w.div(w.sub(nper, num(1)), num(2)))))
+			if due == DueBegin {
+				df = w.add(df, w.mul(pmt, nper))
+			}
+			return f, df
+		}
+		q := w.add(num(1), r)
+		qn := w.pow(q, nper)
+		qn1 := w.sub(qn, num(1))
+		// f = pv*q^n + pmt*(1+r*type)*(q^n-1)/r + fv
+		f := w.add(w.add(w.mul(pv, qn), w.mul(pmt, w.annuity(r, qn, due))), fv)
+		// f' = n*pv*q^(n-1) + pmt*type*(q^n-1)/r + pmt*(1+r*type)*(n*q^(n-1)*r-(q^n-1))/r^2
+		nqn1 := w.div(w.mul(nper, qn), q)
+		df := w.mul(pv, nqn1)
+		if due == DueBegin {
+			df = w.add(df, w.div(w.mul(pmt, qn1), r))
+		}
+		d := w.div(w.sub(w.mul(nqn1, r), qn1), w.mul(r, r))
+		df = w.add(df, w.mul(w.mul(pmt, w.dueFactor(r, due)), d))
+		return f, df
+	}, guessOrDefault(guess))
+}
+
+// annuity returns (1+rate*type)*((1+rate)^nper-1)/rate, where qn is (1+rate)^nper.
+func (w *work) annuity(rate, qn fxd.FixedDecimal, due Due) fxd.FixedDecimal {
+	return w.mul(w.dueFactor(rate, due), w.div(w.sub(qn, num(1)), rate))
+}
+
+// dueFactor returns 1+rate*type.
+func (w *work) dueFactor(rate fxd.FixedDecimal, due Due) fxd.FixedDecimal {
+	if due == DueBegin {
+		return w.add(num(1), rate)
+	}
+	return num(1)
+}
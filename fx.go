@@ -0,0 +1,196 @@
+// Currency conversion
+//
+// FXConverter holds quotes of currency pairs, each with bid and ask
+// rates rounded to a fixed rate scale. A quote of pair FROM/TO is the
+// amount of TO per one unit of FROM.
+// Rate of a pair is found in this order:
+//  1. direct quote of FROM/TO.
+//  2. inverse of quote TO/FROM, where bid and ask are swapped, e.g.
+//     bid(FROM/TO) = 1/ask(TO/FROM).
+//  3. triangulation through the base currency, multiplying rates of
+//     FROM/BASE and BASE/TO, each of which is direct or inverse.
+//
+// Derived rates are computed from the rounded quotes, with inverse rates
+// kept at MaxFrac fractional digits, and rounded half up to the rate
+// scale only once at the end, so conversion is deterministic regardless
+// of how a rate is derived.
+package fxd
+
+// FXSide selects rate of a quote.
+type FXSide uint8
+
+const (
+	FXMid FXSide = iota // average of bid and ask
+	FXBid               // rate at which quote currency is paid for one unit of base currency
+	FXAsk               // rate at which quote currency is charged for one unit of base currency
+)
+
+// FXConverter converts money between currencies.
+type FXConverter struct {
+	base   string
+	scale  int
+	quotes map[fxPair]fxQuote
+}
+
+type fxPair struct {
+	from, to string
+}
+
+type fxQuote struct {
+	bid, mid, ask FixedDecimal
+}
+
+// NewFXConverter creates a converter triangulating through base
+// currency, with rates rounded to scale fractional digits.
+// scale must be less than MaxFrac so inverse rates can be rounded.
+func NewFXConverter(base string, scale int) (*FXConverter, error) {
+	if _, ok := LookupCurrency(base); !ok || scale < 0 || scale >= MaxFrac {
+		return nil, DecErrInvalidValue
+	}
+	return &FXConverter{base: base, scale: scale, quotes: make(map[fxPair]fxQuote)}, nil
+}
+
+// SetRate sets quote of pair from/to.
+// Rates are rounded to rate scale, and must be positive with
+// bid not greater than ask. Use same bid and ask for mid-only quote.
+func (c *FXConverter) SetRate(from, to string, bid, ask FixedDecimal) error {
+	if _, ok := LookupCurrency(from); !ok || from == to {
+		return DecErrInvalidValue
+	}
+	if _, ok := LookupCurrency(to); !ok {
+		return DecErrInvalidValue
+	}
+	if !fxPositive(&bid) || !fxPositive(&ask) {
+		return DecErrInvalidValue
+	}
+	var q fxQuote
+	bid.RoundTo(&q.bid, c.scale)
+	ask.RoundTo(&q.ask, c.scale)
+	if q.bid.allUnitsZero() || q.ask.Less(&q.bid) {
+		return DecErrInvalidValue
+	}
+	var sum FixedDecimal
+	if err := DecimalAdd(&q.bid, &q.ask, &sum); err != nil {
+		return err
+	}
+	if err := DecimalDivInt64(&sum, 2, &q.mid, 1); err != nil {
+		return err
+	}
+	q.mid.Round(c.scale)
+	c.quotes[fxPair{from, to}] = q
+	return nil
+}
+
+// Rate returns rate of given side for pair from/to.
+// Returns DecErrInvalidValue if the rate cannot be derived.
+func (c *FXConverter) Rate(from, to string, side FXSide) (FixedDecimal, error) {
+	if from == to {
+		if _, ok := LookupCurrency(from); !ok {
+			return FixedDecimal{}, DecErrInvalidValue
+		}
+		return DecimalOne(), nil
+	}
+	if r, ok, err := c.pairRate(from, to, side); err != nil {
+		return FixedDecimal{}, err
+	} else if ok {
+		r.Round(c.scale)
+		return r, nil
+	}
+	if from == c.base || to == c.base {
+		return FixedDecimal{}, DecErrInvalidValue
+	}
+	r1, ok, err := c.pairRate(from, c.base, side)
+	if !ok || err != nil {
+		return FixedDecimal{}, orInvalid(err)
+	}
+	r2, ok, err := c.pairRate(c.base, to, side)
+	if !ok || err != nil {
+		return FixedDecimal{}, orInvalid(err)
+	}
+	var res FixedDecimal
+	if err := DecimalMul(&r1, &r2, &res); err != nil {
+		return FixedDecimal{}, err
+	}
+	res.Round(c.scale)
+	return res, nil
+}
+
+// Convert converts money to given currency, rounded to minor unit
+// of the target currency.
+func (c *FXConverter) Convert(m *Money, to string, side FXSide) (Money, error) {
+	cur, ok := LookupCurrency(to)
+	if !ok {
+		return Money{}, DecErrInvalidValue
+	}
+	return c.ConvertTo(m, to, side, int(cur.MinorUnit))
+}
+
+// ConvertTo converts money to given currency, rounded to frac
+// fractional digits.
+func (c *FXConverter) ConvertTo(m *Money, to string, side FXSide, frac int) (Money, error) {
+	rate, err := c.Rate(m.currency.Code, to, side)
+	if err != nil {
+		return Money{}, err
+	}
+	var res Money
+	res.currency, _ = LookupCurrency(to) // known as rate exists
+	if err = DecimalMul(&m.amount, &rate, &res.amount); err != nil {
+		return Money{}, err
+	}
+	res.amount.Round(frac)
+	if res.amount.allUnitsZero() {
+		res.amount.setPos()
+	}
+	return res, nil
+}
+
+// pairRate returns direct or inverse rate of pair from/to, inverse rate
+// is not rounded to rate scale.
+// Returns false if neither quote exists.
+func (c *FXConverter) pairRate(from, to string, side FXSide) (FixedDecimal, bool, error) {
+	if q, ok := c.quotes[fxPair{from, to}]; ok {
+		return q.side(side), true, nil
+	}
+	q, ok := c.quotes[fxPair{to, from}]
+	if !ok {
+		return FixedDecimal{}, false, nil
+	}
+	// bid and ask are swapped in inverse quote
+	switch side {
+	case FXBid:
+		side = FXAsk
+	case FXAsk:
+		side = FXBid
+	}
+	one := DecimalOne()
+	r := q.side(side)
+	var res FixedDecimal
+	// truncated quotient has more digits than rate scale, so rounding is exact
+	if err := DecimalDiv(&one, &r, &res, MaxFrac); err != nil {
+		return FixedDecimal{}, true, err
+	}
+	return res, true, nil
+}
+
+// fxPositive returns true if fd is a finite positive decimal.
+func fxPositive(fd *FixedDecimal) bool {
+	return !fd.IsNaN() && !fd.IsInf() && !fd.IsNeg() && !fd.allUnitsZero()
+}
+
+func (q *fxQuote) side(side FXSide) FixedDecimal {
+	switch side {
+	case FXBid:
+		return q.bid
+	case FXAsk:
+		return q.ask
+	default:
+		return q.mid
+	}
+}
+
+func orInvalid(err error) error {
+	if err == nil {
+		return DecErrInvalidValue
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package fxd
+
+import "testing"
+
+func newTestConverter(t *testing.T) *FXConverter {
+	c, err := NewFXConverter("USD", 6)
+	if err != nil {
+		t.Fatalf("failed %v", err)
+	}
+	for _, q := range [][4]string{
+		{"EUR", "USD", "1.0850", "1.0852"},
+		{"USD", "JPY", "151.20", "151.30"},
+		{"GBP", "USD", "1.2700", "1.2702"},
+		{"CAD", "CHF", "0.65", "0.66"},
+	} {
+		if err := c.SetRate(q[0], q[1], mustParse(q[2]), mustParse(q[3])); err != nil {
+			t.Fatalf("set rate %v failed: %v", q, err)
+		}
+	}
+	return c
+}
+
+func TestFXRate(t *testing.T) {
+	c := newTestConverter(t)
+	type tcase struct {
+		from, to string
+		side     FXSide
+		expected string
+	}
+	for _, tc := range []tcase{
+		// direct
+		{"EUR", "USD", FXBid, "1.085000"},
+		{"EUR", "USD", FXAsk, "1.085200"},
+		{"EUR", "USD", FXMid, "1.085100"},
+		{"USD", "JPY", FXMid, "151.250000"},
+		// inverse
+		{"USD", "EUR", FXBid, "0.921489"},
+		{"USD", "EUR", FXAsk, "0.921659"},
+		{"USD", "EUR", FXMid, "0.921574"},
+		{"JPY", "USD", FXBid, "0.006609"},
+		// triangulation
+		{"EUR", "JPY", FXBid, "164.052000"},
+		{"JPY", "EUR", FXBid, "0.006090"},
+		{"JPY", "EUR", FXAsk, "0.006096"},
+		{"GBP", "EUR", FXMid, "1.170491"},
+		{"EUR", "GBP", FXAsk, "0.854488"},
+		// same currency
+		{"EUR", "EUR", FXBid, "1"},
+	} {
+		r, err := c.Rate(tc.from, tc.to, tc.side)
+		if err != nil || r.ToString(-1) != tc.expected {
+			t.Fatalf("rate %v/%v side %v mismatch: actual=%v,%v, expected=%v", tc.from, tc.to, tc.side, r.ToString(-1), err, tc.expected)
+		}
+	}
+	// inverse rate is not rounded before triangulation
+	c4, _ := NewFXConverter("USD", 4)
+	c4.SetRate("USD", "GBP", mustParse("0.7777"), mustParse("0.7777"))
+	c4.SetRate("USD", "JPY", mustParse("150.1234"), mustParse("150.1234"))
+	if r, err := c4.Rate("GBP", "JPY", FXMid); err != nil || r.ToString(-1) != "193.0351" {
+		t.Fatalf("failed %v %v", r.ToString(-1), err)
+	}
+	if r, err := c4.Rate("GBP", "USD", FXMid); err != nil || r.ToString(-1) != "1.2858" {
+		t.Fatalf("failed %v %v", r.ToString(-1), err)
+	}
+	// no route through base currency
+	for _, pair := range [][2]string{{"CAD", "EUR"}, {"USD", "CHF"}, {"EUR", "XYZ"}, {"XYZ", "XYZ"}} {
+		if _, err := c.Rate(pair[0], pair[1], FXMid); err != DecErrInvalidValue {
+			t.Fatalf("rate %v mismatch: actual=%v", pair, err)
+		}
+	}
+}
+
+func TestFXSetRate(t *testing.T) {
+	c, _ := NewFXConverter("USD", 6)
+	// rates are rounded to rate scale, and so is mid
+	if err := c.SetRate("EUR", "USD", mustParse("1.0000005"), mustParse("1.0000024")); err != nil {
+		t.Fatalf("failed %v", err)
+	}
+	for side, expected := range map[FXSide]string{FXBid: "1.000001", FXAsk: "1.000002", FXMid: "1.000002"} {
+		if r, _ := c.Rate("EUR", "USD", side); r.ToString(-1) != expected {
+			t.Fatalf("side %v mismatch: actual=%v, expected=%v", side, r.ToString(-1), expected)
+		}
+	}
+	for _, q := range [][4]string{
+		{"EUR", "USD", "1.1", "1.0"},
+		{"EUR", "USD", "0", "1.0"},
+		{"EUR", "USD", "-1", "1.0"},
+		{"EUR", "USD", "0.0000001", "1.0"},
+		{"EUR", "USD", "NaN", "1.0"},
+		{"EUR", "USD", "1", "Inf"},
+		{"EUR", "EUR", "1", "1"},
+		{"EUR", "ABC", "1", "1"},
+	} {
+		if err := c.SetRate(q[0], q[1], parseSpecial(q[2]), parseSpecial(q[3])); err != DecErrInvalidValue {
+			t.Fatalf("set rate %v mismatch: actual=%v", q, err)
+		}
+	}
+	if _, err := NewFXConverter("USD", MaxFrac); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := NewFXConverter("ABC", 6); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestFXConvert(t *testing.T) {
+	c := newTestConverter(t)
+	type tcase struct {
+		input    string
+		to       string
+		side     FXSide
+		expected string
+	}
+	for _, tc := range []tcase{
+		{"USD 1,000.00", "EUR", FXBid, "EUR 921.49"},
+		{"USD -1,000.00", "EUR", FXBid, "EUR -921.49"},
+		{"EUR 100", "JPY", FXBid, "JPY 16,405"},
+		{"JPY 1,234,567", "EUR", FXAsk, "EUR 7,525.92"},
+		{"JPY 1", "USD", FXBid, "USD 0.01"},
+		{"JPY -0.4", "USD", FXBid, "USD 0.00"},
+		{"EUR 10", "EUR", FXBid, "EUR 10.00"},
+	} {
+		m := mustMoney(tc.input)
+		res, err := c.Convert(&m, tc.to, tc.side)
+		if err != nil || res.String() != tc.expected {
+			t.Fatalf("convert %v to %v mismatch: actual=%v,%v, expected=%v", tc.input, tc.to, res.String(), err, tc.expected)
+		}
+	}
+	m := mustMoney("USD 1,000")
+	if res, err := c.ConvertTo(&m, "EUR", FXMid, 4); err != nil || res.String() != "EUR 921.5740" {
+		t.Fatalf("failed %v %v", res.String(), err)
+	}
+	if _, err := c.Convert(&m, "CHF", FXMid); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := c.Convert(&m, "ABC", FXMid); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Hashing and canonical form
+//
+// Numerically equal decimals may have different representations,
+// e.g. 1.5 and 1.500 have different frac, and arithmetic results have
+// integral digits rounded up to multiple of 9.
+// Canonical form removes such differences, so it can be compared with
+// Go == operator and used as map key.
+package fxd
+
+import "math/bits"
+
+const (
+	hashPrime1 = 0x9E3779B97F4A7C15
+	hashPrime2 = 0xBF58476D1CE4E5B9
+	hashPrime3 = 0x94D049BB133111EB
+)
+
+// Canonical returns canonical form of this decimal, with trailing
+// fractional zeros removed and exact number of integral digits.
+// All zeros, including negative zero, are canonicalized to positive zero,
+// all NaNs to positive NaN.
+func (fd *FixedDecimal) Canonical() FixedDecimal {
+	var res FixedDecimal
+	res.SetZero()
+	if fd.IsNaN() {
+		res.setNaN()
+		return res
+	}
+	if fd.IsInf() {
+		res.setInf()
+		if fd.IsNeg() {
+			res.setNeg()
+		}
+		return res
+	}
+	var coef [DoubleMaxUnits]int32
+	scale := int(fd.Frac())
+	fd.coefUnits(coef[:], scale)
+	if !unitsNonZero(coef[:]) {
+		return res
+	}
+	for scale > 0 && coef[0]%10 == 0 {
+		unitsDivRem(coef[:], 10)
+		scale--
+	}
+	// arithmetic result may have more integral digits than MaxDigits,
+	// so setCoefUnits is not applicable
+	coefMulPow10(coef[:], getUnits(scale)*DigitsPerUnit-scale)
+	res.setAlignedUnits(coef[:MaxUnits], scale, fd.IsNeg())
+	return res
+}
+
+// Canonicalize converts this decimal to canonical form in place.
+func (fd *FixedDecimal) Canonicalize() {
+	*fd = fd.Canonical()
+}
+
+// Hash64 returns 64-bit hash of this decimal with given seed.
+// Finite decimals with Compare result 0 always have same hash value, and
+// all zeros have same hash value regardless of sign.
+// Compare is not defined for NaN and Infinity: infinities of same sign,
+// which are equal by CompareAny, have same hash value, and so do all NaNs,
+// though NaN is not equal to any value.
+func (fd *FixedDecimal) Hash64(seed uint64) uint64 {
+	c := fd.Canonical()
+	h := hashMix(seed^hashPrime1, uint64(uint8(c.intg))<<8|uint64(uint8(c.frac)))
+	n := c.IntgUnits() + c.FracUnits()
+	for i := 0; i+1 < n; i += 2 {
+		h = hashMix(h, uint64(uint32(c.lsu[i]))|uint64(uint32(c.lsu[i+1]))<<32)
+	}
+	if n%2 == 1 {
+		h = hashMix(h, uint64(uint32(c.lsu[n-1])))
+	}
+	return hashFinalize(h)
+}
+
+func hashMix(h, v uint64) uint64 {
+	hi, lo := bits.Mul64(h^v, hashPrime1)
+	return hi ^ lo
+}
+
+// hashFinalize is finalizer of SplitMix64.
+func hashFinalize(h uint64) uint64 {
+	h = (h ^ h>>30) * hashPrime2
+	h = (h ^ h>>27) * hashPrime3
+	return h ^ h>>31
+}
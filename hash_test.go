@@ -0,0 +1,120 @@
+package fxd
+
+import "testing"
+
+func TestDecimalCanonical(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected string
+	}
+	for _, c := range []tcase{
+		{"0", "0"},
+		{"-0.000", "0"},
+		{"1.500", "1.5"},
+		{"-1.500", "-1.5"},
+		{"100.00", "100"},
+		{"0.000000000100", "0.0000000001"},
+		{"123456789.123456789000", "123456789.123456789"},
+	} {
+		fd := mustParse(c.input)
+		actual := fd.Canonical()
+		if actual.ToString(-1) != c.expected || actual.Compare(&fd) != 0 && !fd.IsZero() {
+			t.Fatalf("canonical %v mismatch: actual=%v, expected=%v", c.input, actual.ToString(-1), c.expected)
+		}
+		expected := mustParse(c.expected)
+		if actual != expected.Canonical() {
+			t.Fatalf("canonical %v mismatch: actual=%v, expected=%v", c.input, actual, expected.Canonical())
+		}
+	}
+	// arithmetic result
+	var sum FixedDecimal
+	one, half := mustParse("1"), mustParse("0.50")
+	if err := DecimalAdd(&one, &half, &sum); err != nil {
+		t.Fatal("failed")
+	}
+	parsed := mustParse("1.5")
+	if sum == parsed || sum.Canonical() != parsed.Canonical() {
+		t.Fatalf("failed %v %v", sum, parsed)
+	}
+	rounded := mustParse("1.54")
+	rounded.Round(1)
+	if rounded.Canonical() != parsed.Canonical() {
+		t.Fatalf("failed %v %v", rounded, parsed)
+	}
+	// integral digits beyond MaxDigits
+	max := mustParse("99999999999999999999999999999999999999999999999999999999999999999")
+	if err := DecimalAdd(&max, &max, &sum); err != nil {
+		t.Fatal("failed")
+	}
+	if c := sum.Canonical(); c.ToString(-1) != sum.ToString(-1) {
+		t.Fatalf("failed %v", c.ToString(-1))
+	}
+	for _, s := range []string{"NaN", "-NaN"} {
+		fd := parseSpecial(s)
+		if c := fd.Canonical(); !c.IsNaN() || c.IsNeg() {
+			t.Fatalf("canonical %v mismatch", s)
+		}
+	}
+	for _, s := range []string{"Inf", "-Inf"} {
+		fd := parseSpecial(s)
+		c := fd.Canonical()
+		if !c.IsInf() || c.IsNeg() != fd.IsNeg() {
+			t.Fatalf("canonical %v mismatch", s)
+		}
+	}
+	fd := mustParse("2.000")
+	fd.Canonicalize()
+	if fd.ToString(-1) != "2" {
+		t.Fatalf("failed %v", fd.ToString(-1))
+	}
+}
+
+func TestDecimalHash64(t *testing.T) {
+	groups := [][]string{
+		{"0", "-0", "0.000", "-0.0"},
+		{"1.5", "1.50", "1.500000000000"},
+		{"-1.5", "-1.50"},
+		{"1000000000", "1000000000.000000000"},
+		{"0.000000001", "0.0000000010"},
+	}
+	hashes := make(map[uint64]int)
+	for i, group := range groups {
+		var h uint64
+		for j, s := range group {
+			fd := mustParse(s)
+			if j == 0 {
+				h = fd.Hash64(42)
+			} else if fd.Hash64(42) != h {
+				t.Fatalf("hash %v mismatch: actual=%x, expected=%x", s, fd.Hash64(42), h)
+			}
+		}
+		if k, ok := hashes[h]; ok {
+			t.Fatalf("hash collision of %v and %v", groups[k][0], group[0])
+		}
+		hashes[h] = i
+	}
+	fd := mustParse("1.5")
+	if fd.Hash64(1) == fd.Hash64(2) {
+		t.Fatal("seed is not used")
+	}
+	// arithmetic results
+	var res FixedDecimal
+	a, b := mustParse("0.75"), mustParse("2")
+	if err := DecimalMul(&a, &b, &res); err != nil || res.Hash64(0) != fd.Hash64(0) {
+		t.Fatalf("failed %v", res.ToString(-1))
+	}
+	// special values, equal by CompareAny
+	inf, negInf, nan, negNaN := parseSpecial("Inf"), parseSpecial("-Inf"), parseSpecial("NaN"), parseSpecial("-NaN")
+	if inf.Hash64(0) == negInf.Hash64(0) || inf.Hash64(0) == nan.Hash64(0) || nan.Hash64(0) != negNaN.Hash64(0) {
+		t.Fatal("failed")
+	}
+	// as map key
+	m := make(map[FixedDecimal]int)
+	for _, s := range []string{"1.5", "1.50", "-0", "0.00", "2"} {
+		fd := mustParse(s)
+		m[fd.Canonical()]++
+	}
+	if len(m) != 3 || m[fd.Canonical()] != 2 {
+		t.Fatalf("failed %v", m)
+	}
+}
@@ -0,0 +1,411 @@
+// IEEE 754-2008 decimal interchange formats
+//
+// decimal64 and decimal128 represent (-1)^sign * coefficient * 10^exponent,
+// with at most 16 and 34 coefficient digits respectively.
+// The coefficient is encoded either as binary integer (BID) or
+// as densely packed decimal (DPD), which packs 3 digits into 10 bits(declet).
+//
+// A finite value whose exponent requires more than MaxFrac fractional digits is
+// rounded with status DecStatusRounded (and DecStatusInexact if non-zero digits
+// are discarded), and a value with more than MaxDigits integral digits is
+// converted to infinity with status DecStatusOverflow.
+// Negative zero is converted to zero because FixedDecimal does not have signed zero.
+// Signaling NaN is converted to NaN with status DecStatusInvalidOperation,
+// NaN payload is not preserved.
+package fxd
+
+const (
+	decimal64Prec         = 16
+	decimal64Bias         = 398
+	decimal64ExpContBits  = 8
+	decimal64Declets      = 5
+	decimal128Prec        = 34
+	decimal128Bias        = 6176
+	decimal128ExpContBits = 12
+	decimal128Declets     = 11
+)
+
+// 5-bit combination field of special values
+const (
+	ieeeCombInf uint64 = 0x1e
+	ieeeCombNaN uint64 = 0x1f
+)
+
+// dpdToBin maps 10-bit declet to 3-digit number.
+var dpdToBin [1024]uint16
+
+// binToDpd maps 3-digit number to 10-bit declet.
+var binToDpd [1000]uint16
+
+func init() {
+	for i := range binToDpd {
+		binToDpd[i] = dpdEncode(i)
+	}
+	for i := range dpdToBin {
+		dpdToBin[i] = dpdDecode(uint16(i))
+	}
+}
+
+// dpdEncode encodes 3 digits into declet.
+// The digits abcd efgh ijkm are encoded into pqr stu v wxy, depending on
+// whether each digit is large(8 or 9):
+//
+//	aei  pqr stu v wxy
+//	000  bcd fgh 0 jkm
+//	001  bcd fgh 1 00m
+//	010  bcd jkh 1 01m
+//	011  bcd 10h 1 11m
+//	100  jkd fgh 1 10m
+//	101  fgd 01h 1 11m
+//	110  jkd 00h 1 11m
+//	111  00d 11h 1 11m
+func dpdEncode(n int) uint16 {
+	d2, d1, d0 := uint16(n/100), uint16(n/10%10), uint16(n%10)
+	large := d2>>3<<2 | d1>>3<<1 | d0>>3
+	switch large {
+	case 0:
+		return d2<<7 | d1<<4 | d0
+	case 1:
+		return d2<<7 | d1<<4 | 0x8 | d0&1
+	case 2:
+		return d2<<7 | d0&6<<4 | d1&1<<4 | 0xa | d0&1
+	case 3:
+		return d2<<7 | 0x40 | d1&1<<4 | 0xe | d0&1
+	case 4:
+		return d0&6<<7 | d2&1<<7 | d1<<4 | 0xc | d0&1
+	case 5:
+		return d1&6<<7 | d2&1<<7 | 0x20 | d1&1<<4 | 0xe | d0&1
+	case 6:
+		return d0&6<<7 | d2&1<<7 | d1&1<<4 | 0xe | d0&1
+	default:
+		return d2&1<<7 | 0x60 | d1&1<<4 | 0xe | d0&1
+	}
+}
+
+// dpdDecode decodes declet into 3-digit number.
+// All 1024 declets are accepted, including the non-canonical ones.
+func dpdDecode(v uint16) uint16 {
+	pqr, stu, y := v>>7&7, v>>4&7, v&1
+	var d2, d1, d0 uint16
+	if v&0x8 == 0 { // v=0: all digits are small
+		return pqr*100 + stu*10 + v&7
+	}
+	switch v >> 1 & 3 { // wx
+	case 0:
+		d2, d1, d0 = pqr, stu, 8|y
+	case 1:
+		d2, d1, d0 = pqr, 8|stu&1, stu&6|y
+	case 2:
+		d2, d1, d0 = 8|pqr&1, stu, pqr&6|y
+	default:
+		switch stu >> 1 { // st
+		case 0:
+			d2, d1, d0 = 8|pqr&1, 8|stu&1, pqr&6|y
+		case 1:
+			d2, d1, d0 = 8|pqr&1, pqr&6|stu&1, 8|y
+		case 2:
+			d2, d1, d0 = pqr, 8|stu&1, 8|y
+		default:
+			d2, d1, d0 = 8|pqr&1, 8|stu&1, 8|y
+		}
+	}
+	return d2*100 + d1*10 + d0
+}
+
+// DecimalFromDecimal64BID creates a new decimal from IEEE 754 decimal64 in BID encoding.
+func DecimalFromDecimal64BID(v uint64) (FixedDecimal, DecStatus) {
+	var fd FixedDecimal
+	status := fd.FromDecimal64BID(v)
+	return fd, status
+}
+
+// DecimalFromDecimal64DPD creates a new decimal from IEEE 754 decimal64 in DPD encoding.
+func DecimalFromDecimal64DPD(v uint64) (FixedDecimal, DecStatus) {
+	var fd FixedDecimal
+	status := fd.FromDecimal64DPD(v)
+	return fd, status
+}
+
+// DecimalFromDecimal128BID creates a new decimal from IEEE 754 decimal128 in BID encoding.
+// hi and lo are the high and low 64 bits.
+func DecimalFromDecimal128BID(hi, lo uint64) (FixedDecimal, DecStatus) {
+	var fd FixedDecimal
+	status := fd.FromDecimal128BID(hi, lo)
+	return fd, status
+}
+
+// DecimalFromDecimal128DPD creates a new decimal from IEEE 754 decimal128 in DPD encoding.
+// hi and lo are the high and low 64 bits.
+func DecimalFromDecimal128DPD(hi, lo uint64) (FixedDecimal, DecStatus) {
+	var fd FixedDecimal
+	status := fd.FromDecimal128DPD(hi, lo)
+	return fd, status
+}
+
+// FromDecimal64BID sets value of IEEE 754 decimal64 in BID encoding to current decimal.
+func (fd *FixedDecimal) FromDecimal64BID(v uint64) DecStatus {
+	neg := v>>63 != 0
+	comb := v >> 58 & 0x1f
+	if comb >= ieeeCombInf {
+		return fd.fromIEEESpecial(neg, comb, v>>57&1 != 0)
+	}
+	var exp int
+	var c uint64
+	if v>>61&3 == 3 { // coefficient starts with implicit 0b100
+		exp = int(v >> 51 & 0x3ff)
+		c = 1<<53 | v&(1<<51-1)
+	} else {
+		exp = int(v >> 53 & 0x3ff)
+		c = v & (1<<53 - 1)
+	}
+	var coef [DoubleMaxUnits]int32
+	words := [1]uint64{c}
+	wordsToUnits(words[:], coef[:])
+	if unitsDigits(coef[:]) > decimal64Prec { // non-canonical coefficient is treated as zero
+		coef = [DoubleMaxUnits]int32{}
+	}
+	return fd.fromIEEEFinite(neg, coef[:], exp-decimal64Bias)
+}
+
+// FromDecimal128BID sets value of IEEE 754 decimal128 in BID encoding to current decimal.
+// hi and lo are the high and low 64 bits.
+func (fd *FixedDecimal) FromDecimal128BID(hi, lo uint64) DecStatus {
+	neg := hi>>63 != 0
+	comb := hi >> 58 & 0x1f
+	if comb >= ieeeCombInf {
+		return fd.fromIEEESpecial(neg, comb, hi>>57&1 != 0)
+	}
+	var coef [DoubleMaxUnits]int32
+	var exp int
+	if hi>>61&3 == 3 { // coefficient exceeds 2^113, always non-canonical
+		exp = int(hi >> 47 & 0x3fff)
+	} else {
+		exp = int(hi >> 49 & 0x3fff)
+		words := [2]uint64{lo, hi & (1<<49 - 1)}
+		wordsToUnits(words[:], coef[:])
+		if unitsDigits(coef[:]) > decimal128Prec { // non-canonical coefficient is treated as zero
+			coef = [DoubleMaxUnits]int32{}
+		}
+	}
+	return fd.fromIEEEFinite(neg, coef[:], exp-decimal128Bias)
+}
+
+// FromDecimal64DPD sets value of IEEE 754 decimal64 in DPD encoding to current decimal.
+func (fd *FixedDecimal) FromDecimal64DPD(v uint64) DecStatus {
+	neg := v>>63 != 0
+	comb := v >> 58 & 0x1f
+	if comb >= ieeeCombInf {
+		return fd.fromIEEESpecial(neg, comb, v>>57&1 != 0)
+	}
+	expMsb, lead := dpdCombination(comb)
+	exp := int(expMsb<<decimal64ExpContBits | v>>50&0xff)
+	var coef [DoubleMaxUnits]int32
+	coef[0] = int32(lead)
+	for i := decimal64Declets - 1; i >= 0; i-- {
+		unitsMulAdd(coef[:], 1000, int64(dpdToBin[v>>(10*i)&0x3ff]))
+	}
+	return fd.fromIEEEFinite(neg, coef[:], exp-decimal64Bias)
+}
+
+// FromDecimal128DPD sets value of IEEE 754 decimal128 in DPD encoding to current decimal.
+// hi and lo are the high and low 64 bits.
+func (fd *FixedDecimal) FromDecimal128DPD(hi, lo uint64) DecStatus {
+	neg := hi>>63 != 0
+	comb := hi >> 58 & 0x1f
+	if comb >= ieeeCombInf {
+		return fd.fromIEEESpecial(neg, comb, hi>>57&1 != 0)
+	}
+	expMsb, lead := dpdCombination(comb)
+	exp := int(expMsb<<decimal128ExpContBits | hi>>46&0xfff)
+	var coef [DoubleMaxUnits]int32
+	coef[0] = int32(lead)
+	for i := decimal128Declets - 1; i >= 0; i-- {
+		unitsMulAdd(coef[:], 1000, int64(dpdToBin[bits128(hi, lo, uint(10*i))&0x3ff]))
+	}
+	return fd.fromIEEEFinite(neg, coef[:], exp-decimal128Bias)
+}
+
+// dpdCombination extracts exponent's most significant 2 bits and leading
+// digit from combination field of DPD encoding.
+func dpdCombination(comb uint64) (uint64, uint64) {
+	if comb>>3 == 3 {
+		return comb >> 1 & 3, 8 | comb&1
+	}
+	return comb >> 3, comb & 7
+}
+
+// bits128 returns the 128-bit value shifted right by n bits, truncated to 64 bits.
+func bits128(hi, lo uint64, n uint) uint64 {
+	if n >= 64 {
+		return hi >> (n - 64)
+	}
+	if n == 0 {
+		return lo
+	}
+	return lo>>n | hi<<(64-n)
+}
+
+func (fd *FixedDecimal) fromIEEESpecial(neg bool, comb uint64, signaling bool) DecStatus {
+	fd.SetZero()
+	if comb == ieeeCombInf {
+		fd.setInf()
+		if neg {
+			fd.setNeg()
+		}
+		return DecStatusOk
+	}
+	fd.setNaN()
+	if signaling {
+		return DecStatusInvalidOperation
+	}
+	return DecStatusOk
+}
+
+func (fd *FixedDecimal) fromIEEEFinite(neg bool, coef []int32, exp int) DecStatus {
+	status := DecStatusOk
+	scale := -exp
+	if scale > MaxFrac {
+		status |= DecStatusRounded
+		if coefDivPow10HalfUp(coef, scale-MaxFrac) {
+			status |= DecStatusInexact
+		}
+		scale = MaxFrac
+	}
+	if err := fd.setCoefUnits(coef, scale, neg); err != nil {
+		fd.SetZero()
+		fd.setInf()
+		if neg {
+			fd.setNeg()
+		}
+		return status | DecStatusOverflow | DecStatusInexact | DecStatusRounded
+	}
+	return status
+}
+
+// ToDecimal64BID converts this decimal to IEEE 754 decimal64 in BID encoding.
+// Coefficient exceeding 16 digits is rounded with RoundHalfUp.
+func (fd *FixedDecimal) ToDecimal64BID() (uint64, DecStatus) {
+	if sign, comb, ok := fd.ieeeSpecial(); ok {
+		return sign | comb<<58, DecStatusOk
+	}
+	var coef [DoubleMaxUnits]int32
+	exp, status := fd.ieeeCoef(coef[:], decimal64Prec)
+	var words [1]uint64
+	unitsToWords(coef[:], words[:])
+	c := words[0]
+	e := uint64(exp + decimal64Bias)
+	v := fd.ieeeSign()
+	if c < 1<<53 {
+		v |= e<<53 | c
+	} else {
+		v |= 3<<61 | e<<51 | c&(1<<51-1)
+	}
+	return v, status
+}
+
+// ToDecimal128BID converts this decimal to IEEE 754 decimal128 in BID encoding,
+// returns the high and low 64 bits.
+// Coefficient exceeding 34 digits is rounded with RoundHalfUp.
+func (fd *FixedDecimal) ToDecimal128BID() (uint64, uint64, DecStatus) {
+	if sign, comb, ok := fd.ieeeSpecial(); ok {
+		return sign | comb<<58, 0, DecStatusOk
+	}
+	var coef [DoubleMaxUnits]int32
+	exp, status := fd.ieeeCoef(coef[:], decimal128Prec)
+	var words [2]uint64
+	unitsToWords(coef[:], words[:])
+	e := uint64(exp + decimal128Bias)
+	return fd.ieeeSign() | e<<49 | words[1], words[0], status
+}
+
+// ToDecimal64DPD converts this decimal to IEEE 754 decimal64 in DPD encoding.
+// Coefficient exceeding 16 digits is rounded with RoundHalfUp.
+func (fd *FixedDecimal) ToDecimal64DPD() (uint64, DecStatus) {
+	if sign, comb, ok := fd.ieeeSpecial(); ok {
+		return sign | comb<<58, DecStatusOk
+	}
+	var coef [DoubleMaxUnits]int32
+	exp, status := fd.ieeeCoef(coef[:], decimal64Prec)
+	v := fd.ieeeSign()
+	for i := 0; i < decimal64Declets; i++ {
+		v |= uint64(binToDpd[unitsDivRem(coef[:], 1000)]) << (10 * i)
+	}
+	e := uint64(exp + decimal64Bias)
+	v |= dpdCombinationOf(e>>decimal64ExpContBits, uint64(coef[0]))<<58 | e&0xff<<50
+	return v, status
+}
+
+// ToDecimal128DPD converts this decimal to IEEE 754 decimal128 in DPD encoding,
+// returns the high and low 64 bits.
+// Coefficient exceeding 34 digits is rounded with RoundHalfUp.
+func (fd *FixedDecimal) ToDecimal128DPD() (uint64, uint64, DecStatus) {
+	if sign, comb, ok := fd.ieeeSpecial(); ok {
+		return sign | comb<<58, 0, DecStatusOk
+	}
+	var coef [DoubleMaxUnits]int32
+	exp, status := fd.ieeeCoef(coef[:], decimal128Prec)
+	var hi, lo uint64
+	for i := 0; i < decimal128Declets; i++ {
+		d := uint64(binToDpd[unitsDivRem(coef[:], 1000)])
+		n := uint(10 * i)
+		if n >= 64 {
+			hi |= d << (n - 64)
+		} else {
+			lo |= d << n
+			if n+10 > 64 { // straddle both words
+				hi |= d >> (64 - n)
+			}
+		}
+	}
+	e := uint64(exp + decimal128Bias)
+	hi |= fd.ieeeSign() | dpdCombinationOf(e>>decimal128ExpContBits, uint64(coef[0]))<<58 | e&0xfff<<46
+	return hi, lo, status
+}
+
+// dpdCombinationOf builds combination field of DPD encoding from
+// exponent's most significant 2 bits and leading digit.
+func dpdCombinationOf(expMsb, lead uint64) uint64 {
+	if lead >= 8 {
+		return 0x18 | expMsb<<1 | lead&1
+	}
+	return expMsb<<3 | lead
+}
+
+func (fd *FixedDecimal) ieeeSign() uint64 {
+	if fd.IsNeg() {
+		return 1 << 63
+	}
+	return 0
+}
+
+// ieeeSpecial returns sign bit and combination field if this decimal is NaN or infinity.
+func (fd *FixedDecimal) ieeeSpecial() (uint64, uint64, bool) {
+	if fd.IsNaN() {
+		return 0, ieeeCombNaN, true
+	}
+	if fd.IsInf() {
+		return fd.ieeeSign(), ieeeCombInf, true
+	}
+	return 0, 0, false
+}
+
+// ieeeCoef stores coefficient of this decimal with at most prec digits into coef,
+// and returns the exponent.
+func (fd *FixedDecimal) ieeeCoef(coef []int32, prec int) (int, DecStatus) {
+	status := DecStatusOk
+	scale := int(fd.Frac())
+	fd.coefUnits(coef, scale)
+	exp := -scale
+	if d := unitsDigits(coef) - prec; d > 0 {
+		status |= DecStatusRounded
+		if coefDivPow10HalfUp(coef, d) {
+			status |= DecStatusInexact
+		}
+		exp += d
+		if unitsDigits(coef) > prec { // carry of rounding, e.g. 999.5 -> 1000
+			coefDivPow10(coef, 1)
+			exp++
+		}
+	}
+	return exp, status
+}
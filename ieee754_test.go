@@ -0,0 +1,139 @@
+package fxd
+
+import "testing"
+
+func TestDPDDeclet(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		if dpdToBin[binToDpd[i]] != uint16(i) {
+			t.Fatalf("declet of %v mismatch", i)
+		}
+	}
+	for _, c := range [][2]uint16{{0, 0}, {9, 0x009}, {750, 0x3d0}, {999, 0x0ff}, {888, 0x06e}, {123, 0x0a3}} {
+		if binToDpd[c[0]] != c[1] {
+			t.Fatalf("declet of %v mismatch: actual=%#x, expected=%#x", c[0], binToDpd[c[0]], c[1])
+		}
+	}
+	// non-canonical declets
+	for _, c := range [][2]uint16{{0x16e, 888}, {0x26e, 888}, {0x36e, 888}, {0x3ff, 999}} {
+		if dpdToBin[c[0]] != c[1] {
+			t.Fatalf("declet %#x mismatch: actual=%v, expected=%v", c[0], dpdToBin[c[0]], c[1])
+		}
+	}
+}
+
+func TestDecimalIEEE754(t *testing.T) {
+	type tcase struct {
+		input        string
+		bid64, dpd64 uint64
+		status64     DecStatus
+		bid128       [2]uint64
+		dpd128       [2]uint64
+		status128    DecStatus
+	}
+	const rounded = DecStatusRounded | DecStatusInexact
+	for _, c := range []tcase{
+		{"0", 0x31c0000000000000, 0x2238000000000000, DecStatusOk, [2]uint64{0x3040000000000000, 0}, [2]uint64{0x2208000000000000, 0}, DecStatusOk},
+		{"0.00", 0x3180000000000000, 0x2230000000000000, DecStatusOk, [2]uint64{0x303c000000000000, 0}, [2]uint64{0x2207800000000000, 0}, DecStatusOk},
+		{"1", 0x31c0000000000001, 0x2238000000000001, DecStatusOk, [2]uint64{0x3040000000000000, 1}, [2]uint64{0x2208000000000000, 1}, DecStatusOk},
+		{"-7.50", 0xb1800000000002ee, 0xa2300000000003d0, DecStatusOk, [2]uint64{0xb03c000000000000, 0x2ee}, [2]uint64{0xa207800000000000, 0x3d0}, DecStatusOk},
+		{"9999999999999999", 0x6c7386f26fc0ffff, 0x6e38ff3fcff3fcff, DecStatusOk, [2]uint64{0x3040000000000000, 0x2386f26fc0ffff}, [2]uint64{0x2208000000000000, 0x24ff3fcff3fcff}, DecStatusOk},
+		{"12345678901234567", 0x31e462d53c8abac1, 0x263d34b9c1e28e57, rounded, [2]uint64{0x3040000000000000, 0x2bdc545d6b4b87}, [2]uint64{0x2208000000000000, 0x49c5de08d4d2e7}, DecStatusOk},
+		{"99999999999999995", 0x32038d7ea4c68000, 0x2640000000000000, rounded, [2]uint64{0x3040000000000000, 0x16345785d89fffb}, [2]uint64{0x2208000000000000, 0x17cff3fcff3fe9f}, DecStatusOk},
+		{"10000000000000000", 0x31e38d7ea4c68000, 0x263c000000000000, DecStatusRounded, [2]uint64{0x3040000000000000, 0x2386f26fc10000}, [2]uint64{0x2208000000000000, 0x40000000000000}, DecStatusOk},
+		{"9999999999999999999999999999999999", 0x34238d7ea4c68000, 0x2684000000000000, rounded, [2]uint64{0x3041ed09bead87c0, 0x378d8e63ffffffff}, [2]uint64{0x6e080ff3fcff3fcf, 0xf3fcff3fcff3fcff}, DecStatusOk},
+		{"0.123456789012345678901234567890", 0x2fc462d53c8abac1, 0x25f934b9c1e28e57, rounded, [2]uint64{0x300400018ee90ff6, 0xc373e0ee4e3f0ad2}, [2]uint64{0x220080028e56f3c1, 0x27177823534b9c1e}, DecStatusOk},
+		{"99999999999999999999999999999999999999999999999999999999999999999", 0x38038d7ea4c68000, 0x2700000000000000, rounded, [2]uint64{0x3080314dc6448d93, 0x38c15b0a00000000}, [2]uint64{0x2610000000000000, 0}, rounded},
+		{"-12345678901234567890123456789012345.123456789012345678901234567890", 0xb42462d53c8abac1, 0xa68534b9c1e28e57, rounded, [2]uint64{0xb0423cde6fff9732, 0xde825cd07e96aff3}, [2]uint64{0xa608534b9c1e28e5, 0x6f3c127177823535}, rounded},
+		{"Inf", 0x7800000000000000, 0x7800000000000000, DecStatusOk, [2]uint64{0x7800000000000000, 0}, [2]uint64{0x7800000000000000, 0}, DecStatusOk},
+		{"NaN", 0x7c00000000000000, 0x7c00000000000000, DecStatusOk, [2]uint64{0x7c00000000000000, 0}, [2]uint64{0x7c00000000000000, 0}, DecStatusOk},
+	} {
+		fd, err := DecimalFromAsciiString(c.input)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		bid64, status := fd.ToDecimal64BID()
+		if bid64 != c.bid64 || status != c.status64 {
+			t.Fatalf("decimal64 BID of %v mismatch: actual=%#x(%#x), expected=%#x(%#x)", c.input, bid64, status, c.bid64, c.status64)
+		}
+		dpd64, status := fd.ToDecimal64DPD()
+		if dpd64 != c.dpd64 || status != c.status64 {
+			t.Fatalf("decimal64 DPD of %v mismatch: actual=%#x(%#x), expected=%#x(%#x)", c.input, dpd64, status, c.dpd64, c.status64)
+		}
+		hi, lo, status := fd.ToDecimal128BID()
+		if hi != c.bid128[0] || lo != c.bid128[1] || status != c.status128 {
+			t.Fatalf("decimal128 BID of %v mismatch: actual=%#x %#x(%#x)", c.input, hi, lo, status)
+		}
+		hi, lo, status = fd.ToDecimal128DPD()
+		if hi != c.dpd128[0] || lo != c.dpd128[1] || status != c.status128 {
+			t.Fatalf("decimal128 DPD of %v mismatch: actual=%#x %#x(%#x)", c.input, hi, lo, status)
+		}
+		// BID and DPD must be decoded to identical value
+		fdb, status := DecimalFromDecimal64BID(bid64)
+		fdd, status2 := DecimalFromDecimal64DPD(dpd64)
+		if fdb.ToString(-1) != fdd.ToString(-1) || status != status2 {
+			t.Fatalf("decimal64 of %v mismatch: BID=%v, DPD=%v", c.input, fdb.ToString(-1), fdd.ToString(-1))
+		}
+		if c.status64 == DecStatusOk && fdb.ToString(-1) != fd.ToString(-1) {
+			t.Fatalf("decimal64 of %v mismatch: actual=%v", c.input, fdb.ToString(-1))
+		}
+		fdb, status = DecimalFromDecimal128BID(c.bid128[0], c.bid128[1])
+		fdd, status2 = DecimalFromDecimal128DPD(c.dpd128[0], c.dpd128[1])
+		if fdb.ToString(-1) != fdd.ToString(-1) || status != status2 {
+			t.Fatalf("decimal128 of %v mismatch: BID=%v, DPD=%v", c.input, fdb.ToString(-1), fdd.ToString(-1))
+		}
+		if c.status128 == DecStatusOk && fdb.ToString(-1) != fd.ToString(-1) {
+			t.Fatalf("decimal128 of %v mismatch: actual=%v", c.input, fdb.ToString(-1))
+		}
+	}
+}
+
+func TestDecimalFromIEEE754Special(t *testing.T) {
+	// negative infinity
+	fd, status := DecimalFromDecimal64BID(0xf800000000000000)
+	if !fd.IsInf() || !fd.IsNeg() || status != DecStatusOk {
+		t.Fatal("failed")
+	}
+	hi, _, _ := fd.ToDecimal128DPD()
+	if hi != 0xf800000000000000 {
+		t.Fatalf("failed %#x", hi)
+	}
+	// signaling NaN
+	fd, status = DecimalFromDecimal128DPD(0x7e00000000000000, 0)
+	if !fd.IsNaN() || status != DecStatusInvalidOperation {
+		t.Fatal("failed")
+	}
+	// negative zero
+	fd, status = DecimalFromDecimal64DPD(0xa238000000000000)
+	if fd.IsNeg() || !fd.IsZero() || status != DecStatusOk {
+		t.Fatal("failed")
+	}
+	// non-canonical BID coefficient is zero
+	fd, status = DecimalFromDecimal64BID(0x6ff0000000000000 | 1<<51 - 1)
+	if !fd.IsZero() || status != DecStatusOk {
+		t.Fatalf("failed %v", fd.ToString(-1))
+	}
+	fd, _ = DecimalFromDecimal128BID(0x3041ffffffffffff, 0xffffffffffffffff)
+	if !fd.IsZero() {
+		t.Fatalf("failed %v", fd.ToString(-1))
+	}
+	// 1E+100 overflows
+	fd, status = DecimalFromDecimal64BID(uint64(100+decimal64Bias)<<53 | 1)
+	if !fd.IsInf() || status&DecStatusOverflow == 0 {
+		t.Fatalf("failed %v", fd.ToString(-1))
+	}
+	// -1E+64 fits
+	fd, status = DecimalFromDecimal128BID(1<<63|uint64(64+decimal128Bias)<<49, 1)
+	if fd.ToString(-1) != "-10000000000000000000000000000000000000000000000000000000000000000" || status != DecStatusOk {
+		t.Fatalf("failed %v", fd.ToString(-1))
+	}
+	// 1.5E-30 is rounded to 30 fractional digits
+	fd, status = DecimalFromDecimal64DPD(uint64(-31+decimal64Bias)>>8<<61 | uint64(-31+decimal64Bias)&0xff<<50 | 0x15)
+	if fd.ToString(-1) != "0.000000000000000000000000000002" || status != DecStatusRounded|DecStatusInexact {
+		t.Fatalf("failed %v %#x", fd.ToString(-1), status)
+	}
+	// 1.0E-30 is rounded exactly
+	fd, status = DecimalFromDecimal128BID(uint64(-31+decimal128Bias)<<49, 10)
+	if fd.ToString(-1) != "0.000000000000000000000000000001" || status != DecStatusRounded {
+		t.Fatalf("failed %v %#x", fd.ToString(-1), status)
+	}
+}
@@ -0,0 +1,274 @@
+// Arithmetic with int64 operand
+//
+// Multiplying or dividing a decimal by a small integer, e.g. price by
+// quantity, is very common. Instead of converting the integer to decimal
+// and running the generic algorithm, the integer is used as a single unit
+// multiplier or divisor directly on units of the decimal.
+// Operands which cannot be handled by the fast path fall back to the
+// generic algorithm, so the result always has same value as DecimalAdd, DecimalSub,
+// DecimalMul and DecimalDiv with the integer converted by DecimalFromInt64.
+package fxd
+
+// DecimalAddInt64 adds a normal decimal and an integer.
+func DecimalAddInt64(lhs *FixedDecimal, rhs int64, result *FixedDecimal) error {
+	return addInt64(lhs, rhs < 0, absInt64(rhs), result)
+}
+
+// DecimalSubInt64 subtracts an integer from a normal decimal.
+func DecimalSubInt64(lhs *FixedDecimal, rhs int64, result *FixedDecimal) error {
+	return addInt64(lhs, rhs > 0, absInt64(rhs), result)
+}
+
+// DecimalMulInt64 multiplies a normal decimal by an integer.
+func DecimalMulInt64(lhs *FixedDecimal, rhs int64, result *FixedDecimal) error {
+	mag := absInt64(rhs)
+	if mag >= Unit { // multiplier does not fit in one unit
+		r := int64Decimal(rhs < 0, mag)
+		return DecimalMul(lhs, &r, result)
+	}
+	if mag == 0 || lhs.allUnitsZero() { // no negative zero
+		result.SetZero()
+		return nil
+	}
+	res := *lhs
+	liu, lfu := lhs.IntgUnits(), lhs.FracUnits()
+	if carry := unitsMulAdd(res.lsu[:liu+lfu], int64(mag), 0); carry != 0 {
+		if liu+lfu == MaxUnits { // let generic algorithm truncate fractional part
+			r := int64Decimal(rhs < 0, mag)
+			return DecimalMul(lhs, &r, result)
+		}
+		res.lsu[liu+lfu] = int32(carry)
+		liu++
+	}
+	res.intg = int8(liu * DigitsPerUnit) // expand to multiple of DigitsPerUnit
+	if lhs.IsNeg() != (rhs < 0) {
+		res.setNeg()
+	}
+	*result = res
+	return nil
+}
+
+// DecimalDivInt64 divides a normal decimal by an integer.
+// incrFrac has same meaning as DecimalDiv.
+func DecimalDivInt64(lhs *FixedDecimal, rhs int64, result *FixedDecimal, incrFrac int) error {
+	mag := absInt64(rhs)
+	if mag == 0 {
+		return DecErrDivisionByZero
+	}
+	if mag >= Unit { // divisor does not fit in one unit
+		r := int64Decimal(rhs < 0, mag)
+		return DecimalDiv(lhs, &r, result, incrFrac)
+	}
+	if lhs.allUnitsZero() {
+		result.SetZero()
+		return nil
+	}
+	neg := lhs.IsNeg() != (rhs < 0)
+	lhsFrac := int(lhs.Frac())
+	liu, lfu := lhs.IntgUnits(), lhs.FracUnits()
+	// same fractional precision as DecimalDiv
+	incrFrac -= lfu*DigitsPerUnit - lhsFrac
+	if incrFrac < 0 {
+		incrFrac = 0
+	}
+	resultFracUnits := getUnits(lfu*DigitsPerUnit + incrFrac)
+	if resultFracUnits > MaxFracUnits {
+		resultFracUnits = MaxFracUnits
+	}
+	shift := resultFracUnits - lfu
+	n := shift + liu + lfu
+	if n > MaxUnits { // integral part is too large, extra fractional units are truncated
+		var coef [DoubleMaxUnits]int32
+		copy(coef[shift:], lhs.lsu[:liu+lfu])
+		unitsDivRem(coef[:n], int64(mag))
+		for n > resultFracUnits && coef[n-1] == 0 {
+			n--
+		}
+		start := 0
+		if n > MaxUnits {
+			start = n - MaxUnits
+			resultFracUnits -= start
+		}
+		result.setAlignedUnits(coef[start:start+MaxUnits], resultFracUnits*DigitsPerUnit, neg)
+		return nil
+	}
+	// short division with dividend shifted by extended fractional units
+	var res FixedDecimal
+	var rem int64
+	d := int64(mag)
+	for i := n - 1; i >= 0; i-- {
+		u := rem * Unit
+		if i >= shift {
+			u += int64(lhs.lsu[i-shift])
+		}
+		q := u / d
+		rem = u - q*d
+		res.lsu[i] = int32(q)
+	}
+	resultIntgUnits := n - resultFracUnits
+	for resultIntgUnits > 0 && res.lsu[resultFracUnits+resultIntgUnits-1] == 0 {
+		resultIntgUnits--
+	}
+	res.intg = int8(resultIntgUnits * DigitsPerUnit)
+	res.frac = int8(resultFracUnits * DigitsPerUnit)
+	if neg {
+		res.setNegAndCheckZero()
+	}
+	*result = res
+	return nil
+}
+
+// AddInt64 returns sum of this decimal and an integer.
+func (fd FixedDecimal) AddInt64(v int64) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalAddInt64(&fd, v, &res)
+	return res, err
+}
+
+// SubInt64 returns difference of this decimal and an integer.
+func (fd FixedDecimal) SubInt64(v int64) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalSubInt64(&fd, v, &res)
+	return res, err
+}
+
+// MulInt64 returns product of this decimal and an integer.
+func (fd FixedDecimal) MulInt64(v int64) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalMulInt64(&fd, v, &res)
+	return res, err
+}
+
+// DivInt64 returns quotient of this decimal and an integer, with
+// DivIncrFrac more fractional digits.
+func (fd FixedDecimal) DivInt64(v int64) (FixedDecimal, error) {
+	var res FixedDecimal
+	err := DecimalDivInt64(&fd, v, &res, DivIncrFrac)
+	return res, err
+}
+
+// MustAddInt64 is like AddInt64 but panics on error.
+func (fd FixedDecimal) MustAddInt64(v int64) FixedDecimal {
+	return mustDecimal(fd.AddInt64(v))
+}
+
+// MustSubInt64 is like SubInt64 but panics on error.
+func (fd FixedDecimal) MustSubInt64(v int64) FixedDecimal {
+	return mustDecimal(fd.SubInt64(v))
+}
+
+// MustMulInt64 is like MulInt64 but panics on error.
+func (fd FixedDecimal) MustMulInt64(v int64) FixedDecimal {
+	return mustDecimal(fd.MulInt64(v))
+}
+
+// MustDivInt64 is like DivInt64 but panics on error.
+func (fd FixedDecimal) MustDivInt64(v int64) FixedDecimal {
+	return mustDecimal(fd.DivInt64(v))
+}
+
+// CompareInt64 compares this decimal with an integer.
+// Unlike Compare, negative zero is equal to zero.
+func (fd *FixedDecimal) CompareInt64(v int64) int {
+	lneg := fd.IsNeg() && !fd.allUnitsZero()
+	rneg := v < 0
+	if lneg != rneg {
+		if lneg {
+			return -1
+		}
+		return 1
+	}
+	var units [MaxUnits]int32
+	n := int64Units(absInt64(v), &units)
+	c := cmpAbsLsu(fd.IntgUnits(), fd.FracUnits(), &fd.lsu, n, 0, &units)
+	if lneg {
+		return -c
+	}
+	return c
+}
+
+// addInt64 adds integer with given sign and magnitude to lhs.
+// Integral units of lhs are updated with carry or borrow, fractional
+// units are kept as is.
+func addInt64(lhs *FixedDecimal, neg bool, mag uint64, result *FixedDecimal) error {
+	if mag == 0 {
+		*result = *lhs
+		return nil
+	}
+	var units [MaxUnits]int32
+	n := int64Units(mag, &units)
+	liu, lfu := lhs.IntgUnits(), lhs.FracUnits()
+	lneg := lhs.IsNeg()
+	if lhs.allUnitsZero() {
+		lneg = neg
+	}
+	res := *lhs
+	intg := res.lsu[lfu:]
+	if lneg == neg {
+		var carry int32
+		i := 0
+		for ; i < n || carry != 0; i++ {
+			if i == len(intg) {
+				return DecErrOverflow
+			}
+			intg[i], carry = addWithCarry(intg[i], units[i], carry)
+		}
+		liu = maxInt(liu, i)
+	} else {
+		if cmpAbsLsu(liu, lfu, &lhs.lsu, n, 0, &units) < 0 {
+			// sign changes and fractional part has to be complemented
+			r := int64Decimal(neg, mag)
+			return DecimalAdd(lhs, &r, result)
+		}
+		var borrow int32
+		for i := 0; i < n || borrow != 0; i++ {
+			intg[i], borrow = subWithBorrow(intg[i], units[i], borrow)
+		}
+		for liu > 0 && intg[liu-1] == 0 {
+			liu--
+		}
+	}
+	res.intg = int8(liu * DigitsPerUnit) // expand to multiple of DigitsPerUnit
+	if lneg {
+		res.setNegAndCheckZero()
+	}
+	*result = res
+	return nil
+}
+
+// absInt64 returns absolute value of v, including math.MinInt64.
+func absInt64(v int64) uint64 {
+	if v < 0 {
+		return -uint64(v)
+	}
+	return uint64(v)
+}
+
+// int64Units stores magnitude as little-endian units,
+// returns number of units.
+func int64Units(mag uint64, units *[MaxUnits]int32) int {
+	var n int
+	for mag != 0 {
+		q := mag / Unit
+		units[n] = int32(mag - q*Unit)
+		mag = q
+		n++
+	}
+	return n
+}
+
+// int64Decimal returns decimal of integer with given sign and magnitude.
+// Integral digits are expanded to multiple of DigitsPerUnit like
+// DecimalFromInt64, because precision of generic multiplication and
+// division depends on them.
+func int64Decimal(neg bool, mag uint64) FixedDecimal {
+	var fd FixedDecimal
+	fd.SetZero()
+	if n := int64Units(mag, &fd.lsu); n > 0 {
+		fd.intg = int8(n * DigitsPerUnit)
+		if neg {
+			fd.setNeg()
+		}
+	}
+	return fd
+}
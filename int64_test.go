@@ -0,0 +1,227 @@
+package fxd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecimalArithInt64(t *testing.T) {
+	type tcase struct {
+		lhs string
+		rhs int64
+		add string
+		sub string
+		mul string
+		div string
+	}
+	for _, c := range []tcase{
+		{"1.5", 2, "3.5", "-0.5", "3.0", "0.750000000"},
+		{"-1.5", 2, "0.5", "-3.5", "-3.0", "-0.750000000"},
+		{"1.5", -2, "-0.5", "3.5", "-3.0", "-0.750000000"},
+		{"0", 3, "3", "-3", "0", "0"},
+		{"0.00", -3, "-3.00", "3.00", "0", "0"},
+		{"5", -5, "0", "10", "-25", "-1.000000000"},
+		{"-5.00", 5, "0", "-10.00", "-25.00", "-1.000000000"},
+		{"-5.25", 5, "-0.25", "-10.25", "-26.25", "-1.050000000"},
+		{"10", 3, "13", "7", "30", "3.333333333"},
+		{"999999999.999999999", 1, "1000000000.999999999", "999999998.999999999", "999999999.999999999", "999999999.999999999000000000"},
+		{"123456789012345678.9", 1000000000, "123456790012345678.9", "123456788012345678.9", "123456789012345678900000000.0", "123456789.012345678"},
+		{"1", math.MaxInt64, "9223372036854775808", "-9223372036854775806", "9223372036854775807", "0.000000000"},
+		{"1", math.MinInt64, "-9223372036854775807", "9223372036854775809", "-9223372036854775808", "0"},
+		{"0.1", 999999999, "999999999.1", "-999999998.9", "99999999.9", "0.000000000"},
+	} {
+		lhs := mustParse(c.lhs)
+		for _, op := range []struct {
+			name     string
+			fast     func() (FixedDecimal, error)
+			expected string
+		}{
+			{"add", func() (FixedDecimal, error) { return lhs.AddInt64(c.rhs) }, c.add},
+			{"sub", func() (FixedDecimal, error) { return lhs.SubInt64(c.rhs) }, c.sub},
+			{"mul", func() (FixedDecimal, error) { return lhs.MulInt64(c.rhs) }, c.mul},
+			{"div", func() (FixedDecimal, error) { return lhs.DivInt64(c.rhs) }, c.div},
+		} {
+			res, err := op.fast()
+			if err != nil {
+				t.Fatalf("%v %v %v failed: %v", op.name, c.lhs, c.rhs, err)
+			}
+			expected := mustParse(op.expected)
+			if res.Compare(&expected) != 0 && !(res.IsZero() && expected.IsZero()) || res.Frac() != expected.Frac() {
+				t.Fatalf("%v %v %v mismatch: actual=%v, expected=%v", op.name, c.lhs, c.rhs, res.ToString(-1), op.expected)
+			}
+		}
+	}
+}
+
+func TestDecimalArithInt64Generic(t *testing.T) {
+	lhsList := []string{
+		"0", "-0.000", "1", "-1", "0.5", "-7.25", "-14", "-0.0000000000000001", "999999999", "1000000000.000000001",
+		"-123456789012345678901234567.123456789", "0.000000000000000000000000000001",
+		"99999999999999999999999999999999999.999999999999999999999999999999",
+		"-9999999999999999999999999999999999999999999999999",
+	}
+	rhsList := []int64{
+		0, 1, -1, 2, -3, 7, 10, 999999999, -999999999, 1000000000, 123456789012,
+		math.MaxInt64, math.MinInt64,
+	}
+	for _, l := range lhsList {
+		lhs := mustParse(l)
+		for _, v := range rhsList {
+			rhs := DecimalFromInt64(v)
+			for _, op := range []struct {
+				name    string
+				fast    func(*FixedDecimal) error
+				generic func(*FixedDecimal) error
+			}{
+				{"add", func(r *FixedDecimal) error { return DecimalAddInt64(&lhs, v, r) }, func(r *FixedDecimal) error { return DecimalAdd(&lhs, &rhs, r) }},
+				{"sub", func(r *FixedDecimal) error { return DecimalSubInt64(&lhs, v, r) }, func(r *FixedDecimal) error { return DecimalSub(&lhs, &rhs, r) }},
+				{"mul", func(r *FixedDecimal) error { return DecimalMulInt64(&lhs, v, r) }, func(r *FixedDecimal) error { return DecimalMul(&lhs, &rhs, r) }},
+				{"div", func(r *FixedDecimal) error { return DecimalDivInt64(&lhs, v, r, DivIncrFrac) }, func(r *FixedDecimal) error { return DecimalDiv(&lhs, &rhs, r, DivIncrFrac) }},
+			} {
+				var actual, expected FixedDecimal
+				err1 := op.fast(&actual)
+				err2 := op.generic(&expected)
+				if err1 != err2 {
+					t.Fatalf("%v %v %v error mismatch: actual=%v, expected=%v", op.name, l, v, err1, err2)
+				}
+				if err1 != nil {
+					continue
+				}
+				if actual.IsZero() && expected.IsZero() {
+					// sum of negative zero and zero keeps sign of lhs, but product is never negative zero
+					if op.name == "mul" && actual.IsNeg() {
+						t.Fatalf("%v %v %v mismatch: actual=%v", op.name, l, v, actual.ToString(-1))
+					}
+					continue
+				}
+				if actual.Compare(&expected) != 0 {
+					t.Fatalf("%v %v %v mismatch: actual=%v, expected=%v", op.name, l, v, actual.ToString(-1), expected.ToString(-1))
+				}
+			}
+			cmp := lhs.CompareInt64(v)
+			expected := lhs.Compare(&rhs)
+			if lhs.IsZero() && v == 0 {
+				expected = 0
+			}
+			if cmp != expected {
+				t.Fatalf("compare %v %v mismatch: actual=%v, expected=%v", l, v, cmp, expected)
+			}
+		}
+	}
+}
+
+func TestDecimalArithInt64Error(t *testing.T) {
+	fd := mustParse("1.5")
+	if _, err := fd.DivInt64(0); err != DecErrDivisionByZero {
+		t.Fatalf("failed %v", err)
+	}
+	// maximum integral digits of arithmetic result
+	var max FixedDecimal
+	for i := range max.lsu {
+		max.lsu[i] = Unit - 1
+	}
+	max.intg = MaxUnits * DigitsPerUnit
+	if _, err := max.AddInt64(1); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := max.MulInt64(10); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if min := DecimalFromInt64(math.MinInt64); min.ToString(-1) != "-9223372036854775808" {
+		t.Fatalf("failed %v", min.ToString(-1))
+	}
+	// result aliases operand
+	if err := DecimalMulInt64(&fd, 3, &fd); err != nil || fd.ToString(-1) != "4.5" {
+		t.Fatalf("failed %v", fd.ToString(-1))
+	}
+	if err := DecimalAddInt64(&fd, -5, &fd); err != nil || fd.ToString(-1) != "-0.5" {
+		t.Fatalf("failed %v", fd.ToString(-1))
+	}
+}
+
+func BenchmarkDecimalMulInt64(b *testing.B) {
+	price := mustParse("12345.6789")
+	var res FixedDecimal
+	for i := 0; i < b.N; i++ {
+		DecimalMulInt64(&price, 37, &res)
+	}
+}
+
+func BenchmarkDecimalMulGeneric(b *testing.B) {
+	price := mustParse("12345.6789")
+	var res FixedDecimal
+	for i := 0; i < b.N; i++ {
+		var qty FixedDecimal
+		qty.FromInt64(37, true)
+		DecimalMul(&price, &qty, &res)
+	}
+}
+
+func BenchmarkDecimalAddInt64(b *testing.B) {
+	price := mustParse("12345.6789")
+	var res FixedDecimal
+	for i := 0; i < b.N; i++ {
+		DecimalAddInt64(&price, 37, &res)
+	}
+}
+
+func BenchmarkDecimalAddGeneric(b *testing.B) {
+	price := mustParse("12345.6789")
+	var res FixedDecimal
+	for i := 0; i < b.N; i++ {
+		var qty FixedDecimal
+		qty.FromInt64(37, true)
+		DecimalAdd(&price, &qty, &res)
+	}
+}
+
+func BenchmarkDecimalDivInt64(b *testing.B) {
+	price := mustParse("12345.6789")
+	var res FixedDecimal
+	for i := 0; i < b.N; i++ {
+		DecimalDivInt64(&price, 37, &res, DivIncrFrac)
+	}
+}
+
+func BenchmarkDecimalDivGeneric(b *testing.B) {
+	price := mustParse("12345.6789")
+	var res FixedDecimal
+	for i := 0; i < b.N; i++ {
+		var qty FixedDecimal
+		qty.FromInt64(37, true)
+		DecimalDiv(&price, &qty, &res, DivIncrFrac)
+	}
+}
+
+func BenchmarkDecimalCompareInt64(b *testing.B) {
+	price := mustParse("12345.6789")
+	for i := 0; i < b.N; i++ {
+		price.CompareInt64(12345)
+	}
+}
+
+func BenchmarkDecimalCompareGeneric(b *testing.B) {
+	price := mustParse("12345.6789")
+	for i := 0; i < b.N; i++ {
+		var v FixedDecimal
+		v.FromInt64(12345, true)
+		price.Compare(&v)
+	}
+}
+
+func TestDecimalInt64Chain(t *testing.T) {
+	a, b := mustParse("1.5"), mustParse("2.25")
+	if res := a.MustAdd(b).MustMulInt64(4).MustSubInt64(5).MustDivInt64(3); res.ToString(-1) != "3.333333333" {
+		t.Fatalf("failed %v", res.ToString(-1))
+	}
+	res, err := a.MustAdd(b).AddInt64(-1)
+	if err != nil || res.ToString(-1) != "2.75" {
+		t.Fatalf("failed %v %v", res.ToString(-1), err)
+	}
+	defer func() {
+		if r := recover(); r != DecErrDivisionByZero {
+			t.Fatalf("failed %v", r)
+		}
+	}()
+	a.MustDivInt64(0)
+}
@@ -0,0 +1,190 @@
+// Monetary amount with currency
+//
+// Money binds a decimal amount to an ISO 4217 currency, so amounts of
+// different currencies cannot be added or compared by mistake:
+// such operations return DecErrCurrencyMismatch.
+// The amount keeps all fractional digits of arithmetic results,
+// Round rounds it to the minor unit of the currency explicitly.
+//
+// Text format is alphabetic code, a space and the amount with
+// comma-grouped integral digits, e.g. "USD 1,234.50" and "JPY -1,000".
+package fxd
+
+// Money is a decimal amount of a currency.
+type Money struct {
+	amount   FixedDecimal
+	currency Currency
+}
+
+// NewMoney creates money of given amount and currency code.
+// Returns DecErrInvalidValue if currency code is unknown or amount
+// is NaN or Infinity.
+func NewMoney(amount FixedDecimal, code string) (Money, error) {
+	cur, ok := LookupCurrency(code)
+	if !ok || amount.IsNaN() || amount.IsInf() {
+		return Money{}, DecErrInvalidValue
+	}
+	return Money{amount, cur}, nil
+}
+
+// Amount returns amount of the money.
+func (m *Money) Amount() FixedDecimal {
+	return m.amount
+}
+
+// Currency returns currency of the money.
+func (m *Money) Currency() Currency {
+	return m.currency
+}
+
+// Add returns sum of two money of same currency.
+func (m *Money) Add(rhs Money) (Money, error) {
+	return m.arith(DecimalAdd, &rhs)
+}
+
+// Sub returns difference of two money of same currency.
+func (m *Money) Sub(rhs Money) (Money, error) {
+	return m.arith(DecimalSub, &rhs)
+}
+
+// Mul returns money multiplied by a factor.
+func (m *Money) Mul(factor FixedDecimal) (Money, error) {
+	res := Money{currency: m.currency}
+	err := DecimalMul(&m.amount, &factor, &res.amount)
+	return res, err
+}
+
+// Div returns money divided by a divisor, with DivIncrFrac more
+// fractional digits.
+func (m *Money) Div(divisor FixedDecimal) (Money, error) {
+	res := Money{currency: m.currency}
+	err := DecimalDiv(&m.amount, &divisor, &res.amount, DivIncrFrac)
+	return res, err
+}
+
+// Neg returns money with negated amount.
+func (m *Money) Neg() Money {
+	res := *m
+	res.amount.Neg()
+	return res
+}
+
+// Round returns money with amount rounded to minor unit of the currency.
+func (m *Money) Round() Money {
+	res := Money{currency: m.currency}
+	m.amount.RoundTo(&res.amount, int(m.currency.MinorUnit))
+	if res.amount.allUnitsZero() { // no negative zero, e.g. "JPY -0.4"
+		res.amount.setPos()
+	}
+	return res
+}
+
+// Compare compares amounts of two money of same currency.
+func (m *Money) Compare(rhs Money) (int, error) {
+	if m.currency != rhs.currency {
+		return 0, DecErrCurrencyMismatch
+	}
+	c, _ := m.amount.CompareAny(&rhs.amount) // amount is never NaN
+	return c, nil
+}
+
+func (m *Money) arith(op func(lhs, rhs, result *FixedDecimal) error, rhs *Money) (Money, error) {
+	if m.currency != rhs.currency {
+		return Money{}, DecErrCurrencyMismatch
+	}
+	res := Money{currency: m.currency}
+	err := op(&m.amount, &rhs.amount, &res.amount)
+	return res, err
+}
+
+// String formats the money as "USD 1,234.50".
+// Amount with less fractional digits than minor unit is padded
+// with zeroes, extra fractional digits are kept.
+func (m *Money) String() string {
+	frac := maxInt(int(m.amount.Frac()), int(m.currency.MinorUnit))
+	digits := m.amount.ToString(frac)
+	buf := make([]byte, 0, len(m.currency.Code)+1+len(digits)+len(digits)/3)
+	buf = append(buf, m.currency.Code...)
+	buf = append(buf, ' ')
+	if digits[0] == '-' {
+		buf = append(buf, '-')
+		digits = digits[1:]
+	}
+	intg := len(digits)
+	for i := 0; i < len(digits); i++ {
+		if digits[i] == '.' {
+			intg = i
+			break
+		}
+	}
+	for i := 0; i < intg; i++ {
+		if i > 0 && (intg-i)%3 == 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, digits[i])
+	}
+	buf = append(buf, digits[intg:]...)
+	return string(buf)
+}
+
+// ParseMoney parses money in format of String.
+// Grouping of integral digits is optional, but if present, every group
+// except the first must have exactly 3 digits. Exponent is not allowed.
+func ParseMoney(s string) (Money, error) {
+	sp := -1
+	for i := 0; i < len(s); i++ {
+		if s[i] == ' ' {
+			sp = i
+			break
+		}
+	}
+	if sp < 0 {
+		return Money{}, DecErrConversionSyntax
+	}
+	cur, ok := LookupCurrency(s[:sp])
+	if !ok {
+		return Money{}, DecErrInvalidValue
+	}
+	bs := make([]byte, 0, len(s)-sp-1)
+	i := sp + 1
+	if i < len(s) && (s[i] == '-' || s[i] == '+') {
+		bs = append(bs, s[i])
+		i++
+	}
+	group := 0       // digits in current group
+	grouped := false // whether any separator is found
+	for ; i < len(s) && s[i] != '.'; i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			bs = append(bs, c)
+			group++
+		case c == ',':
+			if group == 0 || (grouped && group != 3) || (!grouped && group > 3) {
+				return Money{}, DecErrConversionSyntax
+			}
+			grouped = true
+			group = 0
+		default:
+			return Money{}, DecErrConversionSyntax
+		}
+	}
+	if grouped && group != 3 {
+		return Money{}, DecErrConversionSyntax
+	}
+	for j := i + 1; j < len(s); j++ { // fractional part has only digits
+		if !isDigit(s[j]) {
+			return Money{}, DecErrConversionSyntax
+		}
+	}
+	bs = append(bs, s[i:]...)
+	var res Money
+	res.currency = cur
+	if err := res.amount.FromBytesString(bs, true); err != nil {
+		return Money{}, err
+	}
+	if res.amount.IsNaN() || res.amount.IsInf() {
+		return Money{}, DecErrConversionSyntax
+	}
+	return res, nil
+}
@@ -0,0 +1,175 @@
+package fxd
+
+import "testing"
+
+func mustMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestCurrencyTable(t *testing.T) {
+	all := Currencies()
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Code >= all[i].Code {
+			t.Fatalf("table is not sorted at %v", all[i].Code)
+		}
+	}
+	type tcase struct {
+		code      string
+		numeric   uint16
+		minorUnit int8
+	}
+	for _, c := range []tcase{
+		{"USD", 840, 2},
+		{"JPY", 392, 0},
+		{"KWD", 414, 3},
+		{"CLF", 990, 4},
+		{"AED", 784, 2},
+		{"ZWG", 924, 2},
+	} {
+		cur, ok := LookupCurrency(c.code)
+		if !ok || cur.Numeric != c.numeric || cur.MinorUnit != c.minorUnit {
+			t.Fatalf("currency %v mismatch: actual=%v, expected=%v", c.code, cur, c)
+		}
+		if cur, ok = LookupCurrencyNumeric(c.numeric); !ok || cur.Code != c.code {
+			t.Fatalf("currency %v mismatch: actual=%v", c.numeric, cur)
+		}
+	}
+	for _, code := range []string{"usd", "XXX", "XAU", "", "USDX"} {
+		if _, ok := LookupCurrency(code); ok {
+			t.Fatalf("currency %v should not exist", code)
+		}
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected string
+	}
+	for _, c := range []tcase{
+		{"USD 1234.5", "USD 1,234.50"},
+		{"USD 1,234.50", "USD 1,234.50"},
+		{"USD -1234567.891", "USD -1,234,567.891"},
+		{"USD +12", "USD 12.00"},
+		{"USD 0", "USD 0.00"},
+		{"USD 123", "USD 123.00"},
+		{"USD 123456", "USD 123,456.00"},
+		{"JPY 1000", "JPY 1,000"},
+		{"JPY -100", "JPY -100"},
+		{"KWD 1.5", "KWD 1.500"},
+		{"EUR .5", "EUR 0.50"},
+	} {
+		m, err := ParseMoney(c.input)
+		if err != nil {
+			t.Fatalf("parse %v failed: %v", c.input, err)
+		}
+		if m.String() != c.expected {
+			t.Fatalf("format %v mismatch: actual=%v, expected=%v", c.input, m.String(), c.expected)
+		}
+	}
+	type ecase struct {
+		input string
+		err   error
+	}
+	for _, c := range []ecase{
+		{"USD", DecErrConversionSyntax},
+		{"1234.50", DecErrConversionSyntax},
+		{"ABC 1.00", DecErrInvalidValue},
+		{"usd 1.00", DecErrInvalidValue},
+		{"USD 1,23.00", DecErrConversionSyntax},
+		{"USD 1234,567", DecErrConversionSyntax},
+		{"USD ,123", DecErrConversionSyntax},
+		{"USD 1,,234", DecErrConversionSyntax},
+		{"USD 1,234,56", DecErrConversionSyntax},
+		{"USD  1.00", DecErrConversionSyntax},
+		{"USD 1.0x", DecErrConversionSyntax},
+		{"USD 1.5e3", DecErrConversionSyntax},
+		{"USD 1.5E-3", DecErrConversionSyntax},
+		{"USD 1.-5", DecErrConversionSyntax},
+		{"USD 1. 5", DecErrConversionSyntax},
+		{"USD NaN", DecErrConversionSyntax},
+		{"USD ", DecErrConversionSyntax},
+	} {
+		if _, err := ParseMoney(c.input); err != c.err {
+			t.Fatalf("parse %v mismatch: actual=%v, expected=%v", c.input, err, c.err)
+		}
+	}
+}
+
+func TestMoneyArith(t *testing.T) {
+	usd1 := mustMoney("USD 1,234.50")
+	usd2 := mustMoney("USD 0.125")
+	jpy := mustMoney("JPY 100")
+	if res, err := usd1.Add(usd2); err != nil || res.String() != "USD 1,234.625" {
+		t.Fatalf("failed %v %v", res.String(), err)
+	}
+	if res, err := usd2.Sub(usd1); err != nil || res.String() != "USD -1,234.375" {
+		t.Fatalf("failed %v %v", res.String(), err)
+	}
+	if res, err := usd1.Mul(mustParse("3")); err != nil || res.String() != "USD 3,703.50" {
+		t.Fatalf("failed %v %v", res.String(), err)
+	}
+	if res, err := usd1.Div(mustParse("3")); err != nil || res.String() != "USD 411.500000000" {
+		t.Fatalf("failed %v %v", res.String(), err)
+	}
+	if _, err := usd1.Div(mustParse("0")); err != DecErrDivisionByZero {
+		t.Fatalf("failed %v", err)
+	}
+	if res := usd1.Neg(); res.String() != "USD -1,234.50" {
+		t.Fatalf("failed %v", res.String())
+	}
+	if _, err := usd1.Add(jpy); err != DecErrCurrencyMismatch {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := jpy.Sub(usd1); err != DecErrCurrencyMismatch {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := usd1.Compare(jpy); err != DecErrCurrencyMismatch {
+		t.Fatalf("failed %v", err)
+	}
+	if c, err := usd2.Compare(usd1); err != nil || c != -1 {
+		t.Fatalf("failed %v %v", c, err)
+	}
+	if c, err := usd1.Compare(mustMoney("USD 1234.5000")); err != nil || c != 0 {
+		t.Fatalf("failed %v %v", c, err)
+	}
+	cur := usd1.Currency()
+	amount := usd1.Amount()
+	if cur.Code != "USD" || amount.ToString(-1) != "1234.50" {
+		t.Fatalf("failed %v %v", cur, amount.ToString(-1))
+	}
+	if _, err := NewMoney(mustParse("1"), "XYZ"); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := NewMoney(parseSpecial("Inf"), "USD"); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected string
+	}
+	for _, c := range []tcase{
+		{"USD 1.005", "USD 1.01"},
+		{"USD -1.005", "USD -1.01"},
+		{"USD 1.004", "USD 1.00"},
+		{"USD 1.5", "USD 1.50"},
+		{"JPY 99.5", "JPY 100"},
+		{"JPY -0.4", "JPY 0"},
+		{"KWD 0.0005", "KWD 0.001"},
+		{"CLF 1.23456", "CLF 1.2346"},
+	} {
+		m := mustMoney(c.input)
+		res := m.Round()
+		amount := res.Amount()
+		if res.String() != c.expected || int(amount.Frac()) != int(res.Currency().MinorUnit) {
+			t.Fatalf("round %v mismatch: actual=%v, expected=%v", c.input, res.String(), c.expected)
+		}
+	}
+}
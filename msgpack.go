@@ -0,0 +1,123 @@
+// MessagePack extension type
+//
+// Decimal is stored as extension with application-defined type code,
+// the data is the scale followed by the unscaled integer:
+//
+//	| scale (int8) | unscaled (big-endian two's complement, minimal length) |
+//
+// value = unscaled * 10^(-scale).
+package fxd
+
+const (
+	msgpackFixext1  = 0xd4
+	msgpackFixext2  = 0xd5
+	msgpackFixext4  = 0xd6
+	msgpackFixext8  = 0xd7
+	msgpackFixext16 = 0xd8
+	msgpackExt8     = 0xc7
+	msgpackExt16    = 0xc8
+	msgpackExt32    = 0xc9
+)
+
+// AppendMsgpackExtData appends data of MessagePack extension of this decimal
+// to given buffer, the extension header is not included.
+// Returns DecErrInvalidValue if this decimal is NaN or Inf.
+func (fd *FixedDecimal) AppendMsgpackExtData(buf []byte) ([]byte, error) {
+	if fd.IsNaN() || fd.IsInf() {
+		return buf, DecErrInvalidValue
+	}
+	scale := int(fd.Frac())
+	var words [protoMaxWords]uint64
+	if err := fd.unscaledWords(words[:], MaxDigits, scale); err != nil {
+		return buf, err
+	}
+	n := len(buf)
+	buf = append(buf, byte(int8(scale)))
+	buf = append(buf, make([]byte, minTwosComplementLen(words[:]))...)
+	putWordsBE(buf[n+1:], words[:])
+	return buf, nil
+}
+
+// AppendMsgpackExt appends this decimal as MessagePack extension with
+// given type code to buffer.
+func (fd *FixedDecimal) AppendMsgpackExt(buf []byte, typ int8) ([]byte, error) {
+	var data [32]byte // scale and at most 28 bytes of unscaled integer
+	payload, err := fd.AppendMsgpackExtData(data[:0])
+	if err != nil {
+		return buf, err
+	}
+	switch len(payload) {
+	case 2:
+		buf = append(buf, msgpackFixext2)
+	case 4:
+		buf = append(buf, msgpackFixext4)
+	case 8:
+		buf = append(buf, msgpackFixext8)
+	case 16:
+		buf = append(buf, msgpackFixext16)
+	default:
+		buf = append(buf, msgpackExt8, byte(len(payload)))
+	}
+	buf = append(buf, byte(typ))
+	return append(buf, payload...), nil
+}
+
+// DecodeMsgpackExtData creates a new decimal from data of MessagePack extension.
+func DecodeMsgpackExtData(data []byte) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromMsgpackExtData(data)
+	return fd, err
+}
+
+// FromMsgpackExtData parses data of MessagePack extension and set value to
+// current decimal.
+// Returns DecErrOverflow if the value cannot be held by this decimal.
+func (fd *FixedDecimal) FromMsgpackExtData(data []byte) error {
+	if len(data) < 2 {
+		return DecErrConversionSyntax
+	}
+	var words [protoMaxWords]uint64
+	if !getWordsBE(data[1:], words[:]) {
+		return DecErrOverflow
+	}
+	return fd.fromUnscaledWords(words[:], MaxDigits, int(int8(data[0])))
+}
+
+// DecodeMsgpackExt creates a new decimal from MessagePack extension with
+// given type code.
+func DecodeMsgpackExt(bs []byte, typ int8) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromMsgpackExt(bs, typ)
+	return fd, err
+}
+
+// FromMsgpackExt parses MessagePack extension with given type code and set
+// value to current decimal. The input must contain exactly one extension.
+func (fd *FixedDecimal) FromMsgpackExt(bs []byte, typ int8) error {
+	if len(bs) < 2 {
+		return DecErrConversionSyntax
+	}
+	var n, hdr int
+	switch bs[0] {
+	case msgpackFixext1, msgpackFixext2, msgpackFixext4, msgpackFixext8, msgpackFixext16:
+		n, hdr = 1<<(bs[0]-msgpackFixext1), 1
+	case msgpackExt8:
+		n, hdr = int(bs[1]), 2
+	case msgpackExt16:
+		if len(bs) < 3 {
+			return DecErrConversionSyntax
+		}
+		n, hdr = int(bs[1])<<8|int(bs[2]), 3
+	case msgpackExt32:
+		if len(bs) < 5 {
+			return DecErrConversionSyntax
+		}
+		n, hdr = int(bs[1])<<24|int(bs[2])<<16|int(bs[3])<<8|int(bs[4]), 5
+	default:
+		return DecErrConversionSyntax
+	}
+	if len(bs) < hdr+1 || int8(bs[hdr]) != typ || len(bs)-hdr-1 != n {
+		return DecErrConversionSyntax
+	}
+	return fd.FromMsgpackExtData(bs[hdr+1:])
+}
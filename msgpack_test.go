@@ -0,0 +1,66 @@
+package fxd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecimalMsgpackExt(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected string
+	}
+	for _, c := range []tcase{
+		{"0", "d5050000"},
+		{"1.23", "d50502" + "7b"},
+		{"-1.23", "d5050285"},
+		{"128", "c70305000080"},
+		{"-0.000000000000000000000000000001", "d5051eff"},
+		{"9223372036854775807", "c7090500" + "7fffffffffffffff"},
+		{"-99999999999999999999999999999999999.999999999999999999999999999999",
+			"c71d051eff0ce9d8e3803c6f757410b9b1c6ba1085dac9f60000000000000001"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		actual, err := fd.AppendMsgpackExt(nil, 5)
+		if err != nil || hex.EncodeToString(actual) != c.expected {
+			t.Fatalf("encode %v mismatch: actual=%x, expected=%v, err=%v", c.input, actual, c.expected, err)
+		}
+		fd2, err := DecodeMsgpackExt(actual, 5)
+		if err != nil || fd2.ToString(-1) != fd.ToString(-1) {
+			t.Fatalf("decode %v mismatch: actual=%v, err=%v", c.input, fd2.ToString(-1), err)
+		}
+	}
+	type ecase struct {
+		input string
+		err   error
+	}
+	for _, c := range []ecase{
+		// ext16 and ext32
+		{"c800020502" + "7b", nil},
+		{"c90000000205027b", nil},
+		{"d5060000", DecErrConversionSyntax},
+		{"d505027b00", DecErrConversionSyntax},
+		{"d405", DecErrConversionSyntax},
+		{"d4050a", DecErrConversionSyntax},
+		{"c0", DecErrConversionSyntax},
+		// scale 31
+		{"d5051f01", DecErrOverflow},
+		// scale -66
+		{"d505be01", DecErrOverflow},
+	} {
+		bs, _ := hex.DecodeString(c.input)
+		fd, err := DecodeMsgpackExt(bs, 5)
+		if err != c.err {
+			t.Fatalf("decode %v mismatch: err=%v, expected=%v", c.input, err, c.err)
+		}
+		if err == nil && fd.ToString(-1) != "1.23" {
+			t.Fatalf("decode %v mismatch: actual=%v", c.input, fd.ToString(-1))
+		}
+	}
+	var inf FixedDecimal
+	inf.SetZero()
+	inf.setInf()
+	if _, err := inf.AppendMsgpackExt(nil, 5); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
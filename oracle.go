@@ -0,0 +1,146 @@
+// Oracle NUMBER internal format
+//
+// NUMBER is stored as an exponent byte followed by up to 20 base-100
+// mantissa bytes, trailing zero digits are not stored:
+//
+//	| exponent | digit[0] ... digit[n-1] | [terminator] |
+//
+// value = sum(d[i] * 100^(e-i)).
+// For positive number, exponent byte is e+193 and mantissa byte is d+1.
+// For negative number, exponent byte is 62-e and mantissa byte is 101-d,
+// followed by terminator 102 if there are less than 20 digits.
+// Zero is single byte 0x80, positive infinity is 0xFF 0x65 and negative
+// infinity is single byte 0x00.
+package fxd
+
+const (
+	oraNumberZero       = 0x80
+	oraNumberPosExpBias = 193
+	oraNumberNegExpBias = 62
+	oraNumberNegTerm    = 102
+	oraNumberMaxDigits  = 20
+	oraNumberBase       = 100
+)
+
+// OraNumberMaxLen is maximum byte length of Oracle NUMBER.
+const OraNumberMaxLen = 1 + oraNumberMaxDigits + 1
+
+// AppendOraNumber appends this decimal in Oracle NUMBER internal format to
+// given buffer.
+// Returns DecErrOverflow if significant digits exceed 20 base-100 digits,
+// or DecErrInvalidValue if this decimal is NaN.
+func (fd *FixedDecimal) AppendOraNumber(buf []byte) ([]byte, error) {
+	if fd.IsNaN() {
+		return buf, DecErrInvalidValue
+	}
+	if fd.IsInf() {
+		if fd.IsNeg() {
+			return append(buf, 0x00), nil
+		}
+		return append(buf, 0xFF, 0x65), nil
+	}
+	// fractional digits must be aligned to base-100 digits
+	scale := int(fd.Frac()+1) / 2 * 2
+	var coef [DoubleMaxUnits]int32
+	fd.coefUnits(coef[:], scale)
+	// convert base-1e9 units to base-100 digits, from least significant one
+	var digits [DoubleMaxUnits*DigitsPerUnit/2 + 1]byte
+	var n int
+	for unitsNonZero(coef[:]) {
+		digits[n] = byte(unitsDivRem(coef[:], oraNumberBase))
+		n++
+	}
+	if n == 0 {
+		return append(buf, oraNumberZero), nil
+	}
+	var low int // trailing zeros are not stored
+	for digits[low] == 0 {
+		low++
+	}
+	if n-low > oraNumberMaxDigits {
+		return buf, DecErrOverflow
+	}
+	e := n - 1 - scale/2
+	if !fd.IsNeg() {
+		buf = append(buf, byte(e+oraNumberPosExpBias))
+		for i := n - 1; i >= low; i-- {
+			buf = append(buf, digits[i]+1)
+		}
+		return buf, nil
+	}
+	buf = append(buf, byte(oraNumberNegExpBias-e))
+	for i := n - 1; i >= low; i-- {
+		buf = append(buf, oraNumberBase+1-digits[i])
+	}
+	if n-low < oraNumberMaxDigits {
+		buf = append(buf, oraNumberNegTerm)
+	}
+	return buf, nil
+}
+
+// DecodeOraNumber creates a new decimal from Oracle NUMBER internal format.
+func DecodeOraNumber(bs []byte) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromOraNumber(bs, false)
+	return fd, err
+}
+
+// FromOraNumber parses Oracle NUMBER internal format and set value to current decimal.
+// if reset=true, will always reset current decimal before parsing.
+// Returns DecErrOverflow if integral digits exceed MaxDigits, or fractional
+// digits exceed MaxFrac.
+func (fd *FixedDecimal) FromOraNumber(bs []byte, reset bool) error {
+	if reset {
+		fd.Reset()
+	}
+	if len(bs) == 0 {
+		return DecErrConversionSyntax
+	}
+	switch {
+	case len(bs) == 1 && bs[0] == oraNumberZero:
+		fd.SetZero()
+		return nil
+	case len(bs) == 1 && bs[0] == 0x00:
+		fd.SetZero()
+		fd.setInf()
+		fd.setNeg()
+		return nil
+	case len(bs) == 2 && bs[0] == 0xFF && bs[1] == 0x65:
+		fd.SetZero()
+		fd.setInf()
+		return nil
+	}
+	neg := bs[0] < oraNumberZero
+	mantissa := bs[1:]
+	var e int
+	if neg {
+		e = oraNumberNegExpBias - int(bs[0])
+		if len(mantissa) > 0 && mantissa[len(mantissa)-1] == oraNumberNegTerm {
+			mantissa = mantissa[:len(mantissa)-1]
+		}
+	} else {
+		e = int(bs[0]) - oraNumberPosExpBias
+	}
+	if len(mantissa) == 0 || len(mantissa) > oraNumberMaxDigits {
+		return DecErrConversionSyntax
+	}
+	var coef [DoubleMaxUnits]int32
+	var d int
+	for _, b := range mantissa {
+		if neg {
+			d = oraNumberBase + 1 - int(b)
+		} else {
+			d = int(b) - 1
+		}
+		if d < 0 || d >= oraNumberBase {
+			return DecErrConversionSyntax
+		}
+		unitsMulAdd(coef[:], oraNumberBase, int64(d))
+	}
+	scale := 2 * (len(mantissa) - 1 - e)
+	if scale > 0 && d%10 == 0 { // trailing zero of last base-100 digit
+		unitsDivRem(coef[:], 10)
+		scale--
+	}
+	return fd.setCoefUnits(coef[:], scale, neg)
+}
@@ -0,0 +1,100 @@
+package fxd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecimalOraNumber(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected string
+		output   string
+	}
+	for _, c := range []tcase{
+		{"0", "80", "0"},
+		{"0.000", "80", "0"},
+		{"1", "c102", "1"},
+		{"-1", "3e6466", "-1"},
+		{"-5", "3e6066", "-5"},
+		{"100", "c202", "100"},
+		{"0.1", "c00b", "0.1"},
+		{"-0.1", "3f5b66", "-0.1"},
+		{"0.01", "c002", "0.01"},
+		{"0.10", "c00b", "0.1"},
+		{"123.456", "c202182e3d", "123.456"},
+		{"-123.456", "3d644e382966", "-123.456"},
+		{"0.000000000000000000000000000001", "b202", "0.000000000000000000000000000001"},
+		{"99999999999999999999999999999999999999", "d364646464646464646464646464646464646464", "99999999999999999999999999999999999999"},
+		{"12345678901234567890123456789012345678.9", "d30d23394f5b0d23394f5b0d23394f5b0d23394f5b", "12345678901234567890123456789012345678.9"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		actual, err := fd.AppendOraNumber(nil)
+		if err != nil || hex.EncodeToString(actual) != c.expected {
+			t.Fatalf("encode %v mismatch: actual=%x, expected=%v, err=%v", c.input, actual, c.expected, err)
+		}
+		fd2, err := DecodeOraNumber(actual)
+		if err != nil || fd2.ToString(-1) != c.output {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v, err=%v", c.input, fd2.ToString(-1), c.output, err)
+		}
+	}
+	var fd FixedDecimal
+	fd.SetZero()
+	fd.setInf()
+	if actual, _ := fd.AppendOraNumber(nil); !bytes.Equal(actual, []byte{0xFF, 0x65}) {
+		t.Fatalf("+Inf mismatch: actual=%x", actual)
+	}
+	if fd2, err := DecodeOraNumber([]byte{0xFF, 0x65}); err != nil || !fd2.IsInf() || fd2.IsNeg() {
+		t.Fatal("failed")
+	}
+	fd.setNeg()
+	if actual, _ := fd.AppendOraNumber(nil); !bytes.Equal(actual, []byte{0x00}) {
+		t.Fatalf("-Inf mismatch: actual=%x", actual)
+	}
+	if fd2, err := DecodeOraNumber([]byte{0x00}); err != nil || !fd2.IsInf() || !fd2.IsNeg() {
+		t.Fatal("failed")
+	}
+	fd.setNaN()
+	if _, err := fd.AppendOraNumber(nil); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	// more than 20 base-100 digits
+	fd, _ = DecimalFromAsciiString("1234567890123456789012345678901234567890.1")
+	if _, err := fd.AppendOraNumber(nil); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecodeOraNumberError(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected error
+	}
+	for _, c := range []tcase{
+		{"", DecErrConversionSyntax},
+		{"c1", DecErrConversionSyntax},
+		{"c100", DecErrConversionSyntax},
+		{"c166", DecErrConversionSyntax},
+		{"3e66", DecErrConversionSyntax},
+		{"c1020202020202020202020202020202020202020202", DecErrConversionSyntax},
+		// 1e126, integral digits exceed MaxDigits
+		{"ff02", DecErrOverflow},
+		// 1e-130, fractional digits exceed MaxFrac
+		{"8002", DecErrOverflow},
+		// 1e-32
+		{"b102", DecErrOverflow},
+		// -1e126
+		{"006466", DecErrOverflow},
+	} {
+		bs, _ := hex.DecodeString(c.input)
+		if _, err := DecodeOraNumber(bs); err != c.expected {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v", c.input, err, c.expected)
+		}
+	}
+	// 1e64 still fits
+	fd, err := DecodeOraNumber([]byte{0xE1, 0x02})
+	if err != nil || fd.ToString(-1) != "1"+string(bytes.Repeat([]byte{'0'}, 64)) {
+		t.Fatalf("failed %v %v", fd.ToString(-1), err)
+	}
+}
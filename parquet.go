@@ -0,0 +1,209 @@
+// Parquet and Avro decimal logical types
+//
+// Parquet annotates DECIMAL(prec, scale) on several physical types,
+// all of them store the unscaled integer:
+//
+//	INT32: precision up to 9.
+//	INT64: precision up to 18.
+//	FIXED_LEN_BYTE_ARRAY: big-endian two's complement, with minimal byte width for the precision.
+//	BINARY: big-endian two's complement, with minimal byte width for the value.
+//
+// Avro decimal logical type uses the same big-endian two's complement
+// encoding on "bytes" and "fixed" types.
+package fxd
+
+const (
+	ParquetInt32MaxPrec = 9
+	ParquetInt64MaxPrec = 18
+)
+
+// maximum byte width of unscaled integer
+const parquetMaxWords = 4
+
+// ToParquetInt32 converts this decimal to unscaled integer of Parquet
+// INT32 DECIMAL(prec, scale). Fractional digits beyond scale are rounded.
+func (fd *FixedDecimal) ToParquetInt32(prec, scale int) (int32, error) {
+	if prec <= 0 || prec > ParquetInt32MaxPrec {
+		return 0, DecErrInvalidType
+	}
+	var words [1]uint64
+	if err := fd.unscaledWords(words[:], prec, scale); err != nil {
+		return 0, err
+	}
+	return int32(words[0]), nil
+}
+
+// ToParquetInt64 converts this decimal to unscaled integer of Parquet
+// INT64 DECIMAL(prec, scale). Fractional digits beyond scale are rounded.
+func (fd *FixedDecimal) ToParquetInt64(prec, scale int) (int64, error) {
+	if prec <= 0 || prec > ParquetInt64MaxPrec {
+		return 0, DecErrInvalidType
+	}
+	var words [1]uint64
+	if err := fd.unscaledWords(words[:], prec, scale); err != nil {
+		return 0, err
+	}
+	return int64(words[0]), nil
+}
+
+// DecimalFromParquetInt32 creates a new decimal from unscaled integer of
+// Parquet INT32 DECIMAL(prec, scale).
+func DecimalFromParquetInt32(v int32, prec, scale int) (FixedDecimal, error) {
+	var fd FixedDecimal
+	if prec <= 0 || prec > ParquetInt32MaxPrec {
+		return fd, DecErrInvalidType
+	}
+	words := [1]uint64{uint64(int64(v))}
+	err := fd.fromUnscaledWords(words[:], prec, scale)
+	return fd, err
+}
+
+// DecimalFromParquetInt64 creates a new decimal from unscaled integer of
+// Parquet INT64 DECIMAL(prec, scale).
+func DecimalFromParquetInt64(v int64, prec, scale int) (FixedDecimal, error) {
+	var fd FixedDecimal
+	if prec <= 0 || prec > ParquetInt64MaxPrec {
+		return fd, DecErrInvalidType
+	}
+	words := [1]uint64{uint64(v)}
+	err := fd.fromUnscaledWords(words[:], prec, scale)
+	return fd, err
+}
+
+// parquetFixedLens maps precision to minimal byte width of FIXED_LEN_BYTE_ARRAY.
+var parquetFixedLens [MaxDigits + 1]uint8
+
+func init() {
+	// n bytes can store 2^(8n-1)-1, which has one digit less than 2^(8n-1)
+	// because a power of two is never a power of ten
+	var pow2 [DoubleMaxUnits]int32
+	pow2[0] = 1 << 7
+	n := 1
+	for prec := range parquetFixedLens {
+		for unitsDigits(pow2[:])-1 < prec {
+			unitsMulAdd(pow2[:], 1<<8, 0)
+			n++
+		}
+		parquetFixedLens[prec] = uint8(n)
+	}
+}
+
+// ParquetFixedLen returns minimal byte width of FIXED_LEN_BYTE_ARRAY
+// to store any value of DECIMAL(prec, scale).
+// Returns 0 if prec is out of range [1, MaxDigits].
+func ParquetFixedLen(prec int) int {
+	if prec <= 0 || prec > MaxDigits {
+		return 0
+	}
+	return int(parquetFixedLens[prec])
+}
+
+// AppendParquetFixedLen appends this decimal as Parquet FIXED_LEN_BYTE_ARRAY
+// DECIMAL(prec, scale) value to given buffer, with byte width of ParquetFixedLen(prec).
+func (fd *FixedDecimal) AppendParquetFixedLen(buf []byte, prec, scale int) ([]byte, error) {
+	if prec <= 0 || prec > MaxDigits {
+		return buf, DecErrInvalidType
+	}
+	return fd.appendBigEndianFixed(buf, ParquetFixedLen(prec), prec, scale)
+}
+
+// AppendParquetBinary appends this decimal as Parquet BINARY DECIMAL(prec, scale)
+// value to given buffer, with minimal byte width of the value.
+func (fd *FixedDecimal) AppendParquetBinary(buf []byte, prec, scale int) ([]byte, error) {
+	if prec <= 0 {
+		return buf, DecErrInvalidType
+	}
+	var words [parquetMaxWords]uint64
+	if err := fd.unscaledWords(words[:], prec, scale); err != nil {
+		return buf, err
+	}
+	n := len(buf)
+	buf = append(buf, make([]byte, minTwosComplementLen(words[:]))...)
+	putWordsBE(buf[n:], words[:])
+	return buf, nil
+}
+
+func (fd *FixedDecimal) appendBigEndianFixed(buf []byte, size, prec, scale int) ([]byte, error) {
+	var words [parquetMaxWords]uint64
+	if err := fd.unscaledWords(words[:], prec, scale); err != nil {
+		return buf, err
+	}
+	if minTwosComplementLen(words[:]) > size {
+		return buf, DecErrOverflow
+	}
+	n := len(buf)
+	buf = append(buf, make([]byte, size)...)
+	putWordsBE(buf[n:], words[:])
+	return buf, nil
+}
+
+// DecodeParquetFixedLen creates a new decimal from Parquet FIXED_LEN_BYTE_ARRAY
+// DECIMAL(prec, scale) value.
+func DecodeParquetFixedLen(bs []byte, prec, scale int) (FixedDecimal, error) {
+	var fd FixedDecimal
+	if prec <= 0 || prec > MaxDigits {
+		return fd, DecErrInvalidType
+	}
+	if len(bs) != ParquetFixedLen(prec) {
+		return fd, DecErrConversionSyntax
+	}
+	err := fd.fromBigEndian(bs, prec, scale)
+	return fd, err
+}
+
+// DecodeParquetBinary creates a new decimal from Parquet BINARY
+// DECIMAL(prec, scale) value.
+func DecodeParquetBinary(bs []byte, prec, scale int) (FixedDecimal, error) {
+	var fd FixedDecimal
+	if prec <= 0 {
+		return fd, DecErrInvalidType
+	}
+	err := fd.fromBigEndian(bs, prec, scale)
+	return fd, err
+}
+
+func (fd *FixedDecimal) fromBigEndian(bs []byte, prec, scale int) error {
+	if len(bs) == 0 {
+		return DecErrConversionSyntax
+	}
+	var words [parquetMaxWords]uint64
+	if !getWordsBE(bs, words[:]) {
+		return DecErrOverflow
+	}
+	return fd.fromUnscaledWords(words[:], prec, scale)
+}
+
+// AppendAvroBytes appends this decimal as payload of Avro "bytes" with
+// decimal(prec, scale) logical type to given buffer.
+// The length prefix of Avro binary encoding is not included.
+func (fd *FixedDecimal) AppendAvroBytes(buf []byte, prec, scale int) ([]byte, error) {
+	return fd.AppendParquetBinary(buf, prec, scale)
+}
+
+// AppendAvroFixed appends this decimal as Avro "fixed" of given size with
+// decimal(prec, scale) logical type to given buffer.
+func (fd *FixedDecimal) AppendAvroFixed(buf []byte, size, prec, scale int) ([]byte, error) {
+	if prec <= 0 || size <= 0 {
+		return buf, DecErrInvalidType
+	}
+	return fd.appendBigEndianFixed(buf, size, prec, scale)
+}
+
+// DecodeAvroBytes creates a new decimal from payload of Avro "bytes" with
+// decimal(prec, scale) logical type.
+func DecodeAvroBytes(bs []byte, prec, scale int) (FixedDecimal, error) {
+	return DecodeParquetBinary(bs, prec, scale)
+}
+
+// DecodeAvroFixed creates a new decimal from Avro "fixed" of given size with
+// decimal(prec, scale) logical type.
+// Returns DecErrConversionSyntax if length of bs is not size.
+func DecodeAvroFixed(bs []byte, size, prec, scale int) (FixedDecimal, error) {
+	if prec <= 0 || size <= 0 {
+		return FixedDecimal{}, DecErrInvalidType
+	}
+	if len(bs) != size {
+		return FixedDecimal{}, DecErrConversionSyntax
+	}
+	return DecodeParquetBinary(bs, prec, scale)
+}
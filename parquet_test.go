@@ -0,0 +1,146 @@
+package fxd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParquetFixedLen(t *testing.T) {
+	for _, c := range [][2]int{
+		{1, 1}, {2, 1}, {3, 2}, {4, 2}, {5, 3}, {6, 3}, {7, 4}, {9, 4}, {10, 5},
+		{11, 5}, {12, 6}, {14, 6}, {15, 7}, {16, 7}, {17, 8}, {18, 8}, {19, 9},
+		{21, 9}, {22, 10}, {38, 16}, {39, 17}, {64, 27}, {65, 28}, {0, 0}, {66, 0},
+	} {
+		if actual := ParquetFixedLen(c[0]); actual != c[1] {
+			t.Fatalf("precision %v mismatch: actual=%v, expected=%v", c[0], actual, c[1])
+		}
+	}
+}
+
+func TestDecimalParquetInt(t *testing.T) {
+	type tcase struct {
+		input       string
+		prec, scale int
+		expected    int64
+		output      string
+	}
+	for _, c := range []tcase{
+		{"0", 9, 2, 0, "0.00"},
+		{"1.23", 9, 2, 123, "1.23"},
+		{"-1.23", 9, 2, -123, "-1.23"},
+		{"-1.235", 9, 2, -124, "-1.24"},
+		{"9999999.99", 9, 2, 999999999, "9999999.99"},
+		{"1", 9, 0, 1, "1"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		v32, err := fd.ToParquetInt32(c.prec, c.scale)
+		if err != nil || int64(v32) != c.expected {
+			t.Fatalf("int32 %v mismatch: actual=%v, err=%v", c.input, v32, err)
+		}
+		fd2, err := DecimalFromParquetInt32(v32, c.prec, c.scale)
+		if err != nil || fd2.ToString(-1) != c.output {
+			t.Fatalf("int32 %v mismatch: actual=%v, err=%v", c.input, fd2.ToString(-1), err)
+		}
+		v64, err := fd.ToParquetInt64(c.prec, c.scale)
+		if err != nil || v64 != c.expected {
+			t.Fatalf("int64 %v mismatch: actual=%v, err=%v", c.input, v64, err)
+		}
+		fd2, err = DecimalFromParquetInt64(v64, c.prec, c.scale)
+		if err != nil || fd2.ToString(-1) != c.output {
+			t.Fatalf("int64 %v mismatch: actual=%v, err=%v", c.input, fd2.ToString(-1), err)
+		}
+	}
+	fd, _ := DecimalFromAsciiString("-99999999999999999.9")
+	if v, err := fd.ToParquetInt64(18, 1); err != nil || v != -999999999999999999 {
+		t.Fatalf("failed %v %v", v, err)
+	}
+	if _, err := fd.ToParquetInt32(9, 1); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := fd.ToParquetInt32(10, 1); err != DecErrInvalidType {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := fd.ToParquetInt64(19, 1); err != DecErrInvalidType {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := DecimalFromParquetInt32(1000, 3, 0); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecimalParquetBytes(t *testing.T) {
+	type tcase struct {
+		input       string
+		prec, scale int
+		fixed       []byte
+		binary      []byte
+	}
+	for _, c := range []tcase{
+		{"0", 9, 2, []byte{0, 0, 0, 0}, []byte{0}},
+		{"1.27", 9, 2, []byte{0, 0, 0, 0x7f}, []byte{0x7f}},
+		{"1.28", 9, 2, []byte{0, 0, 0, 0x80}, []byte{0, 0x80}},
+		{"-1.28", 9, 2, []byte{0xff, 0xff, 0xff, 0x80}, []byte{0x80}},
+		{"-1.29", 9, 2, []byte{0xff, 0xff, 0xff, 0x7f}, []byte{0xff, 0x7f}},
+		{"-0.01", 4, 2, []byte{0xff, 0xff}, []byte{0xff}},
+		{"12345678901234567890.12", 22, 2, []byte{0, 0x42, 0xed, 0x12, 0x3b, 0x0b, 0xd8, 0x20, 0x3a, 0x14}, []byte{0x42, 0xed, 0x12, 0x3b, 0x0b, 0xd8, 0x20, 0x3a, 0x14}},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		fixed, err := fd.AppendParquetFixedLen(nil, c.prec, c.scale)
+		if err != nil || !bytes.Equal(fixed, c.fixed) {
+			t.Fatalf("fixed %v mismatch: actual=%v, err=%v", c.input, fixed, err)
+		}
+		binary, err := fd.AppendParquetBinary(nil, c.prec, c.scale)
+		if err != nil || !bytes.Equal(binary, c.binary) {
+			t.Fatalf("binary %v mismatch: actual=%v, err=%v", c.input, binary, err)
+		}
+		avro, err := fd.AppendAvroBytes(nil, c.prec, c.scale)
+		if err != nil || !bytes.Equal(avro, c.binary) {
+			t.Fatalf("avro %v mismatch: actual=%v, err=%v", c.input, avro, err)
+		}
+		fd2, err := DecodeParquetFixedLen(fixed, c.prec, c.scale)
+		if err != nil || fd2.Compare(&fd) != 0 {
+			t.Fatalf("fixed %v mismatch: actual=%v, err=%v", c.input, fd2.ToString(-1), err)
+		}
+		fd2, err = DecodeParquetBinary(binary, c.prec, c.scale)
+		if err != nil || fd2.Compare(&fd) != 0 {
+			t.Fatalf("binary %v mismatch: actual=%v, err=%v", c.input, fd2.ToString(-1), err)
+		}
+		fd2, err = DecodeAvroBytes(avro, c.prec, c.scale)
+		if err != nil || fd2.Compare(&fd) != 0 {
+			t.Fatalf("avro %v mismatch: actual=%v, err=%v", c.input, fd2.ToString(-1), err)
+		}
+	}
+}
+
+func TestDecimalAvroFixed(t *testing.T) {
+	fd, _ := DecimalFromAsciiString("-1.5")
+	buf, err := fd.AppendAvroFixed(nil, 40, 10, 1)
+	if err != nil || len(buf) != 40 || !bytes.Equal(buf[:39], bytes.Repeat([]byte{0xff}, 39)) || buf[39] != 0xf1 {
+		t.Fatalf("failed %v %v", buf, err)
+	}
+	fd2, err := DecodeAvroFixed(buf, 40, 10, 1)
+	if err != nil || fd2.Compare(&fd) != 0 {
+		t.Fatalf("failed %v %v", fd2.ToString(-1), err)
+	}
+	if _, err = fd.AppendAvroFixed(nil, 1, 10, 2); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	// non-sign-extended bytes exceed 256 bits
+	long := append([]byte{1}, make([]byte, 40)...)
+	if _, err = DecodeAvroFixed(long, 41, 10, 1); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err = DecodeAvroFixed(buf[1:], 40, 10, 1); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err = DecodeParquetFixedLen([]byte{0, 0, 0}, 9, 2); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err = DecodeParquetBinary(nil, 9, 2); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+	// 2^31 exceeds precision 9
+	if _, err = DecodeParquetBinary([]byte{0, 0x80, 0, 0, 0}, 9, 0); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+// PostgreSQL NUMERIC binary format
+//
+// The binary format of NUMERIC is a header of four 16-bit big-endian
+// integers followed by base-10000 digits:
+//
+//	| ndigits | weight | sign | dscale | digit[0] ... digit[ndigits-1] |
+//
+// value = sum(digit[i] * 10000^(weight-i)), dscale is the display scale.
+// Sign can be positive, negative, NaN, +Infinity or -Infinity.
+package fxd
+
+const (
+	pgNumericPos  uint16 = 0x0000
+	pgNumericNeg  uint16 = 0x4000
+	pgNumericNaN  uint16 = 0xC000
+	pgNumericPInf uint16 = 0xD000
+	pgNumericNInf uint16 = 0xF000
+
+	pgNumericBase          = 10000
+	pgNumericDigitsPerBase = 4
+	pgNumericHeaderLen     = 8
+	pgNumericDscaleMask    = 0x3FFF
+)
+
+// base-10000 digits required by maximum coefficient
+const pgNumericMaxDigits = DoubleMaxUnits*DigitsPerUnit/pgNumericDigitsPerBase + 1
+
+// DecodePgNumeric creates a new decimal from PostgreSQL NUMERIC binary format.
+func DecodePgNumeric(bs []byte) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromPgNumeric(bs, false)
+	return fd, err
+}
+
+// AppendPgNumeric appends this decimal in PostgreSQL NUMERIC binary format
+// to given buffer. Display scale is the fractional digit number of this decimal.
+func (fd *FixedDecimal) AppendPgNumeric(buf []byte) []byte {
+	if fd.IsNaN() {
+		return appendPgNumericHeader(buf, 0, 0, pgNumericNaN, 0)
+	}
+	if fd.IsInf() {
+		if fd.IsNeg() {
+			return appendPgNumericHeader(buf, 0, 0, pgNumericNInf, 0)
+		}
+		return appendPgNumericHeader(buf, 0, 0, pgNumericPInf, 0)
+	}
+	dscale := int(fd.Frac())
+	// fractional digits must be aligned to base-10000 digits
+	scale := (dscale + pgNumericDigitsPerBase - 1) / pgNumericDigitsPerBase * pgNumericDigitsPerBase
+	var coef [DoubleMaxUnits]int32
+	fd.coefUnits(coef[:], scale)
+	// convert base-1e9 units to base-10000 digits, from least significant one
+	var digits [pgNumericMaxDigits]int16
+	var n int
+	for unitsNonZero(coef[:]) {
+		digits[n] = int16(unitsDivRem(coef[:], pgNumericBase))
+		n++
+	}
+	if n == 0 { // zero
+		return appendPgNumericHeader(buf, 0, 0, pgNumericPos, dscale)
+	}
+	var low int // trailing zeros are not stored
+	for digits[low] == 0 {
+		low++
+	}
+	weight := n - 1 - scale/pgNumericDigitsPerBase
+	sign := pgNumericPos
+	if fd.IsNeg() {
+		sign = pgNumericNeg
+	}
+	buf = appendPgNumericHeader(buf, n-low, weight, sign, dscale)
+	for i := n - 1; i >= low; i-- {
+		buf = append(buf, byte(digits[i]>>8), byte(digits[i]))
+	}
+	return buf
+}
+
+func appendPgNumericHeader(buf []byte, ndigits, weight int, sign uint16, dscale int) []byte {
+	return append(buf,
+		byte(ndigits>>8), byte(ndigits),
+		byte(weight>>8), byte(weight),
+		byte(sign>>8), byte(sign),
+		byte(dscale>>8), byte(dscale))
+}
+
+// FromPgNumeric parses PostgreSQL NUMERIC binary format and set value to current decimal.
+// if reset=true, will always reset current decimal before parsing.
+// Returns DecErrOverflow if integral digits or display scale exceeds the limitation.
+// Returns DecErrConversionSyntax if non-zero digits follow the display scale.
+func (fd *FixedDecimal) FromPgNumeric(bs []byte, reset bool) error {
+	if reset {
+		fd.Reset()
+	}
+	if len(bs) < pgNumericHeaderLen {
+		return DecErrConversionSyntax
+	}
+	ndigits := int(int16(uint16(bs[0])<<8 | uint16(bs[1])))
+	weight := int(int16(uint16(bs[2])<<8 | uint16(bs[3])))
+	sign := uint16(bs[4])<<8 | uint16(bs[5])
+	dscale := int(uint16(bs[6])<<8 | uint16(bs[7]))
+	if ndigits < 0 || len(bs) != pgNumericHeaderLen+ndigits*2 || dscale&^pgNumericDscaleMask != 0 {
+		return DecErrConversionSyntax
+	}
+	switch sign {
+	case pgNumericNaN:
+		fd.SetZero()
+		fd.setNaN()
+		return nil
+	case pgNumericPInf:
+		fd.SetZero()
+		fd.setInf()
+		return nil
+	case pgNumericNInf:
+		fd.SetZero()
+		fd.setInf()
+		fd.setNeg()
+		return nil
+	case pgNumericPos, pgNumericNeg:
+	default:
+		return DecErrConversionSyntax
+	}
+	var coef [DoubleMaxUnits]int32
+	for i := 0; i < ndigits; i++ {
+		d := uint16(bs[pgNumericHeaderLen+i*2])<<8 | uint16(bs[pgNumericHeaderLen+i*2+1])
+		if d >= pgNumericBase {
+			return DecErrConversionSyntax
+		}
+		if unitsMulAdd(coef[:], pgNumericBase, int64(d)) != 0 {
+			return DecErrOverflow
+		}
+	}
+	// scale of the coefficient, align it to display scale
+	scale := (ndigits - 1 - weight) * pgNumericDigitsPerBase
+	if scale < dscale {
+		if !coefMulPow10(coef[:], dscale-scale) {
+			return DecErrOverflow
+		}
+	} else if coefDivPow10(coef[:], scale-dscale) { // digits beyond display scale are never sent
+		return DecErrConversionSyntax
+	}
+	return fd.setCoefUnits(coef[:], dscale, sign == pgNumericNeg)
+}
@@ -0,0 +1,105 @@
+package fxd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecimalPgNumeric(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected []byte
+	}
+	for _, c := range []tcase{
+		{"0", []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		{"0.00", []byte{0, 0, 0, 0, 0, 0, 0, 2}},
+		{"1", []byte{0, 1, 0, 0, 0, 0, 0, 0, 0, 1}},
+		{"-1", []byte{0, 1, 0, 0, 0x40, 0, 0, 0, 0, 1}},
+		{"1.5", []byte{0, 2, 0, 0, 0, 0, 0, 1, 0, 1, 0x13, 0x88}},
+		{"10000", []byte{0, 1, 0, 1, 0, 0, 0, 0, 0, 1}},
+		{"12345.678", []byte{0, 3, 0, 1, 0, 0, 0, 3, 0, 1, 0x09, 0x29, 0x1A, 0x7C}},
+		{"-0.0001", []byte{0, 1, 0xFF, 0xFF, 0x40, 0, 0, 4, 0, 1}},
+		{"0.000000000000000000000000000001", []byte{0, 1, 0xFF, 0xF8, 0, 0, 0, 30, 0, 100}},
+		{"123456789.123456789", []byte{0, 6, 0, 2, 0, 0, 0, 9, 0, 1, 0x09, 0x29, 0x1A, 0x85, 0x04, 0xD2, 0x16, 0x2E, 0x23, 0x28}},
+		{"NaN", []byte{0, 0, 0, 0, 0xC0, 0, 0, 0}},
+		{"Inf", []byte{0, 0, 0, 0, 0xD0, 0, 0, 0}},
+	} {
+		fd, err := DecimalFromAsciiString(c.input)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		actual := fd.AppendPgNumeric(nil)
+		if !bytes.Equal(actual, c.expected) {
+			t.Fatalf("encode %v mismatch: actual=%v, expected=%v", c.input, actual, c.expected)
+		}
+		fd2, err := DecodePgNumeric(c.expected)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if fd2.ToString(-1) != fd.ToString(-1) {
+			t.Fatalf("decode %v mismatch: actual=%v", c.input, fd2.ToString(-1))
+		}
+	}
+}
+
+func TestDecimalPgNumericNegInf(t *testing.T) {
+	fd := DecimalZero()
+	fd.setInf()
+	fd.setNeg()
+	expected := []byte{0, 0, 0, 0, 0xF0, 0, 0, 0}
+	if actual := fd.AppendPgNumeric(nil); !bytes.Equal(actual, expected) {
+		t.Fatalf("encode mismatch: actual=%v, expected=%v", actual, expected)
+	}
+	fd2, err := DecodePgNumeric(expected)
+	if err != nil || !fd2.IsInf() || !fd2.IsNeg() {
+		t.Fatal("failed")
+	}
+}
+
+func TestDecimalPgNumericRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"99999999999999999999999999999999999999999999999999999999999999999",
+		"-99999999999999999999999999999999999999999999999999999999999999999",
+		"99999999999999999999999999999999999.999999999999999999999999999999",
+		"0.123456789012345678901234567890",
+		"100000000",
+		"1000000000",
+		"0.1",
+		"0.01",
+		"-0.001",
+		"1.23400",
+	} {
+		fd, err := DecimalFromAsciiString(s)
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		fd2, err := DecodePgNumeric(fd.AppendPgNumeric(nil))
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		if fd2.ToString(-1) != s || fd2.Compare(&fd) != 0 {
+			t.Fatalf("round trip %v mismatch: actual=%v", s, fd2.ToString(-1))
+		}
+	}
+}
+
+func TestDecimalPgNumericError(t *testing.T) {
+	type tcase struct {
+		input    []byte
+		expected error
+	}
+	for _, c := range []tcase{
+		{[]byte{0, 0, 0, 0, 0, 0, 0}, DecErrConversionSyntax},                      // short header
+		{[]byte{0, 1, 0, 0, 0, 0, 0, 0}, DecErrConversionSyntax},                   // missing digits
+		{[]byte{0, 1, 0, 0, 0, 0, 0, 0, 0x27, 0x10}, DecErrConversionSyntax},       // digit 10000
+		{[]byte{0, 0, 0, 0, 0x80, 0, 0, 0}, DecErrConversionSyntax},                // invalid sign
+		{[]byte{0, 0, 0, 0, 0, 0, 0xC0, 0}, DecErrConversionSyntax},                // invalid dscale
+		{[]byte{0, 1, 0, 20, 0, 0, 0, 0, 0, 1}, DecErrOverflow},                    // 10000^20
+		{[]byte{0, 1, 0, 0, 0, 0, 0, 31, 0, 1}, DecErrOverflow},                    // dscale 31
+		{[]byte{0, 2, 0, 0, 0, 0, 0, 1, 0, 1, 0x13, 0x89}, DecErrConversionSyntax}, // 1.5001 with dscale 1
+	} {
+		if _, err := DecodePgNumeric(c.input); err != c.expected {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v", c.input, err, c.expected)
+		}
+	}
+}
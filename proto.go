@@ -0,0 +1,243 @@
+// Protocol Buffers messages
+//
+// ProtoDecimal is message fxd.Decimal defined in proto/decimal.proto:
+//
+//	message Decimal {
+//	  bytes unscaled = 1; // big-endian two's complement, minimal length
+//	  int32 scale = 2;
+//	}
+//
+// ProtoGoogleDecimal is message google.type.Decimal, which stores the
+// decimal as string:
+//
+//	message Decimal {
+//	  string value = 1;
+//	}
+//
+// Both are encoded and decoded in protobuf wire format without protobuf runtime.
+// Generated code of fxd.Decimal is package fxdpb in directory proto.
+package fxd
+
+import "encoding/binary"
+
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+
+	protoDecimalUnscaled = 1
+	protoDecimalScale    = 2
+	protoGoogleValue     = 1
+)
+
+// maximum words of unscaled integer with MaxDigits digits
+const protoMaxWords = 4
+
+// ProtoDecimal is Go representation of message fxd.Decimal,
+// value = Unscaled * 10^(-Scale).
+type ProtoDecimal struct {
+	Unscaled []byte // big-endian two's complement
+	Scale    int32
+}
+
+// ProtoGoogleDecimal is Go representation of message google.type.Decimal.
+type ProtoGoogleDecimal struct {
+	Value string
+}
+
+// ToProto converts this decimal to ProtoDecimal.
+// Scale is the fractional digit number of this decimal.
+// Returns DecErrInvalidValue if this decimal is NaN or Inf.
+func (fd *FixedDecimal) ToProto() (ProtoDecimal, error) {
+	if fd.IsNaN() || fd.IsInf() {
+		return ProtoDecimal{}, DecErrInvalidValue
+	}
+	scale := int(fd.Frac())
+	var words [protoMaxWords]uint64
+	if err := fd.unscaledWords(words[:], MaxDigits, scale); err != nil {
+		return ProtoDecimal{}, err
+	}
+	m := ProtoDecimal{Scale: int32(scale)}
+	if words != [protoMaxWords]uint64{} { // zero is empty
+		m.Unscaled = make([]byte, minTwosComplementLen(words[:]))
+		putWordsBE(m.Unscaled, words[:])
+	}
+	return m, nil
+}
+
+// DecimalFromProto creates a new decimal from ProtoDecimal.
+func DecimalFromProto(m *ProtoDecimal) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromProto(m)
+	return fd, err
+}
+
+// FromProto sets value of ProtoDecimal to current decimal.
+// Returns DecErrOverflow if the value cannot be held by this decimal.
+func (fd *FixedDecimal) FromProto(m *ProtoDecimal) error {
+	var words [protoMaxWords]uint64
+	if len(m.Unscaled) > 0 && !getWordsBE(m.Unscaled, words[:]) {
+		return DecErrOverflow
+	}
+	return fd.fromUnscaledWords(words[:], MaxDigits, int(m.Scale))
+}
+
+// MarshalBinary encodes the message in protobuf wire format.
+func (m *ProtoDecimal) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(nil), nil
+}
+
+// AppendBinary appends the message in protobuf wire format to given buffer.
+// Fields with default value are omitted as proto3 does.
+func (m *ProtoDecimal) AppendBinary(buf []byte) []byte {
+	if len(m.Unscaled) > 0 {
+		buf = appendProtoKey(buf, protoDecimalUnscaled, protoWireBytes)
+		buf = appendUvarint(buf, uint64(len(m.Unscaled)))
+		buf = append(buf, m.Unscaled...)
+	}
+	if m.Scale != 0 {
+		buf = appendProtoKey(buf, protoDecimalScale, protoWireVarint)
+		buf = appendUvarint(buf, uint64(int64(m.Scale))) // int32 is sign-extended
+	}
+	return buf
+}
+
+// UnmarshalBinary decodes the message from protobuf wire format.
+// Unknown fields are skipped.
+func (m *ProtoDecimal) UnmarshalBinary(bs []byte) error {
+	*m = ProtoDecimal{}
+	return walkProtoFields(bs, func(field, wire int, v uint64, data []byte) error {
+		switch {
+		case field == protoDecimalUnscaled && wire == protoWireBytes:
+			m.Unscaled = append(m.Unscaled[:0], data...)
+		case field == protoDecimalScale && wire == protoWireVarint:
+			m.Scale = int32(v)
+		case field == protoDecimalUnscaled || field == protoDecimalScale:
+			return DecErrConversionSyntax
+		}
+		return nil
+	})
+}
+
+// ToProtoGoogle converts this decimal to google.type.Decimal.
+// Returns DecErrInvalidValue if this decimal is NaN or Inf.
+func (fd *FixedDecimal) ToProtoGoogle() (ProtoGoogleDecimal, error) {
+	if fd.IsNaN() || fd.IsInf() {
+		return ProtoGoogleDecimal{}, DecErrInvalidValue
+	}
+	return ProtoGoogleDecimal{Value: string(fd.AppendStringBuffer(nil, -1))}, nil
+}
+
+// DecimalFromProtoGoogle creates a new decimal from google.type.Decimal.
+func DecimalFromProtoGoogle(m *ProtoGoogleDecimal) (FixedDecimal, error) {
+	var fd FixedDecimal
+	err := fd.FromProtoGoogle(m)
+	return fd, err
+}
+
+// FromProtoGoogle sets value of google.type.Decimal to current decimal.
+// Empty value is treated as zero, NaN and Inf are rejected as
+// google.type.Decimal does not allow them.
+func (fd *FixedDecimal) FromProtoGoogle(m *ProtoGoogleDecimal) error {
+	if m.Value == "" {
+		fd.SetZero()
+		return nil
+	}
+	var tmp FixedDecimal
+	if err := tmp.FromAsciiString(m.Value, true); err != nil {
+		return err
+	}
+	if tmp.IsNaN() || tmp.IsInf() {
+		return DecErrConversionSyntax
+	}
+	*fd = tmp
+	return nil
+}
+
+// MarshalBinary encodes the message in protobuf wire format.
+func (m *ProtoGoogleDecimal) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(nil), nil
+}
+
+// AppendBinary appends the message in protobuf wire format to given buffer.
+func (m *ProtoGoogleDecimal) AppendBinary(buf []byte) []byte {
+	if m.Value != "" {
+		buf = appendProtoKey(buf, protoGoogleValue, protoWireBytes)
+		buf = appendUvarint(buf, uint64(len(m.Value)))
+		buf = append(buf, m.Value...)
+	}
+	return buf
+}
+
+// UnmarshalBinary decodes the message from protobuf wire format.
+// Unknown fields are skipped.
+func (m *ProtoGoogleDecimal) UnmarshalBinary(bs []byte) error {
+	*m = ProtoGoogleDecimal{}
+	return walkProtoFields(bs, func(field, wire int, v uint64, data []byte) error {
+		if field == protoGoogleValue {
+			if wire != protoWireBytes {
+				return DecErrConversionSyntax
+			}
+			m.Value = string(data)
+		}
+		return nil
+	})
+}
+
+func appendProtoKey(buf []byte, field, wire int) []byte {
+	return appendUvarint(buf, uint64(field<<3|wire))
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+// walkProtoFields iterates all fields in protobuf wire format.
+// v is the value of varint and fixed fields, data is the payload of
+// length-delimited field.
+func walkProtoFields(bs []byte, fn func(field, wire int, v uint64, data []byte) error) error {
+	for len(bs) > 0 {
+		key, n := binary.Uvarint(bs)
+		if n <= 0 || key>>3 == 0 {
+			return DecErrConversionSyntax
+		}
+		bs = bs[n:]
+		field, wire := int(key>>3), int(key&7)
+		var v uint64
+		var data []byte
+		switch wire {
+		case protoWireVarint:
+			if v, n = binary.Uvarint(bs); n <= 0 {
+				return DecErrConversionSyntax
+			}
+			bs = bs[n:]
+		case protoWireFixed64:
+			if len(bs) < 8 {
+				return DecErrConversionSyntax
+			}
+			v, bs = binary.LittleEndian.Uint64(bs), bs[8:]
+		case protoWireBytes:
+			l, n := binary.Uvarint(bs)
+			if n <= 0 || l > uint64(len(bs)-n) {
+				return DecErrConversionSyntax
+			}
+			data, bs = bs[n:n+int(l)], bs[n+int(l):]
+		case protoWireFixed32:
+			if len(bs) < 4 {
+				return DecErrConversionSyntax
+			}
+			v, bs = uint64(binary.LittleEndian.Uint32(bs)), bs[4:]
+		default:
+			return DecErrConversionSyntax
+		}
+		if err := fn(field, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
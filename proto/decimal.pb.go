@@ -0,0 +1,162 @@
+// Wire representation of fixed-point decimal.
+//
+// The Go type ProtoDecimal in package fxd encodes and decodes this message
+// directly, so fxd does not depend on protobuf runtime. Generated code is
+// decimal.pb.go of package fxdpb, whose tests check both are wire compatible.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: decimal.proto
+
+package fxdpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Decimal represents value unscaled * 10^(-scale).
+type Decimal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unscaled integer in big-endian two's complement with minimal length,
+	// same as Java BigInteger.toByteArray(). Empty means zero.
+	Unscaled []byte `protobuf:"bytes,1,opt,name=unscaled,proto3" json:"unscaled,omitempty"`
+	// Number of fractional digits, may be negative.
+	Scale int32 `protobuf:"varint,2,opt,name=scale,proto3" json:"scale,omitempty"`
+}
+
+func (x *Decimal) Reset() {
+	*x = Decimal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_decimal_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Decimal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Decimal) ProtoMessage() {}
+
+func (x *Decimal) ProtoReflect() protoreflect.Message {
+	mi := &file_decimal_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Decimal.ProtoReflect.Descriptor instead.
+func (*Decimal) Descriptor() ([]byte, []int) {
+	return file_decimal_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Decimal) GetUnscaled() []byte {
+	if x != nil {
+		return x.Unscaled
+	}
+	return nil
+}
+
+func (x *Decimal) GetScale() int32 {
+	if x != nil {
+		return x.Scale
+	}
+	return 0
+}
+
+var File_decimal_proto protoreflect.FileDescriptor
+
+var file_decimal_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x03, 0x66, 0x78, 0x64, 0x22, 0x3b, 0x0a, 0x07, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x12,
+	0x1a, 0x0a, 0x08, 0x75, 0x6e, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x75, 0x6e, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x63, 0x61, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x61, 0x6c,
+	0x65, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x6a, 0x69, 0x61, 0x6e, 0x67, 0x7a, 0x68, 0x65, 0x2f, 0x66, 0x78, 0x64, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x3b, 0x66, 0x78, 0x64, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_decimal_proto_rawDescOnce sync.Once
+	file_decimal_proto_rawDescData = file_decimal_proto_rawDesc
+)
+
+func file_decimal_proto_rawDescGZIP() []byte {
+	file_decimal_proto_rawDescOnce.Do(func() {
+		file_decimal_proto_rawDescData = protoimpl.X.CompressGZIP(file_decimal_proto_rawDescData)
+	})
+	return file_decimal_proto_rawDescData
+}
+
+var file_decimal_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_decimal_proto_goTypes = []interface{}{
+	(*Decimal)(nil), // 0: fxd.Decimal
+}
+var file_decimal_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_decimal_proto_init() }
+func file_decimal_proto_init() {
+	if File_decimal_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_decimal_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Decimal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_decimal_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_decimal_proto_goTypes,
+		DependencyIndexes: file_decimal_proto_depIdxs,
+		MessageInfos:      file_decimal_proto_msgTypes,
+	}.Build()
+	File_decimal_proto = out.File
+	file_decimal_proto_rawDesc = nil
+	file_decimal_proto_goTypes = nil
+	file_decimal_proto_depIdxs = nil
+}
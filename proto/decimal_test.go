@@ -0,0 +1,46 @@
+package fxdpb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jiangzhe/fxd"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"0", "0.00", "1", "-1", "123.45", "-123.45", "127", "128", "-128", "-129", "255", "256",
+		"0.000000000000000000000000000001",
+		"-99999999999999999999999999999999999.999999999999999999999999999999",
+		"99999999999999999999999999999999999999999999999999999999999999999",
+	} {
+		fd, _ := fxd.DecimalFromAsciiString(s)
+		m, err := fd.ToProto()
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		// fxd encoding decoded by generated code
+		bs, _ := m.MarshalBinary()
+		var gen Decimal
+		if err := proto.Unmarshal(bs, &gen); err != nil {
+			t.Fatalf("unmarshal %v failed %v", s, err)
+		}
+		if !bytes.Equal(gen.Unscaled, m.Unscaled) || gen.Scale != m.Scale {
+			t.Fatalf("%v mismatch: actual=%x,%v, expected=%x,%v", s, gen.Unscaled, gen.Scale, m.Unscaled, m.Scale)
+		}
+		// generated encoding decoded by fxd
+		bs, err = proto.Marshal(&gen)
+		if err != nil {
+			t.Fatalf("marshal %v failed %v", s, err)
+		}
+		var m2 fxd.ProtoDecimal
+		if err := m2.UnmarshalBinary(bs); err != nil {
+			t.Fatalf("unmarshal %v failed %v", s, err)
+		}
+		fd2, err := fxd.DecimalFromProto(&m2)
+		if err != nil || fd2.ToString(-1) != fd.ToString(-1) {
+			t.Fatalf("%v mismatch: actual=%v, err=%v", s, fd2.ToString(-1), err)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package fxdpb is generated code of message fxd.Decimal defined in
+// decimal.proto.
+//
+// Package fxd encodes and decodes the same message as fxd.ProtoDecimal
+// without protobuf runtime, tests of this package check both are wire
+// compatible.
+//
+// This package is a separate module, so users of package fxd do not
+// depend on protobuf runtime.
+package fxdpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative decimal.proto
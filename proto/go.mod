@@ -0,0 +1,10 @@
+module github.com/jiangzhe/fxd/proto
+
+go 1.16
+
+require (
+	github.com/jiangzhe/fxd v0.0.0
+	google.golang.org/protobuf v1.28.1
+)
+
+replace github.com/jiangzhe/fxd => ../
@@ -0,0 +1,131 @@
+package fxd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecimalProto(t *testing.T) {
+	type tcase struct {
+		input    string
+		expected string
+		output   string
+	}
+	for _, c := range []tcase{
+		{"0", "", "0"},
+		{"0.00", "1002", "0.00"},
+		{"1.23", "0a017b1002", "1.23"},
+		{"-1.23", "0a01851002", "-1.23"},
+		{"128", "0a020080", "128"},
+		{"-128", "0a0180", "-128"},
+		{"18446744073709551616", "0a09010000000000000000", "18446744073709551616"},
+		{"-0.000000000000000000000000000001", "0a01ff101e", "-0.000000000000000000000000000001"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		m, err := fd.ToProto()
+		if err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		bs, _ := m.MarshalBinary()
+		if hex.EncodeToString(bs) != c.expected {
+			t.Fatalf("encode %v mismatch: actual=%x, expected=%v", c.input, bs, c.expected)
+		}
+		var m2 ProtoDecimal
+		if err = m2.UnmarshalBinary(bs); err != nil {
+			t.Fatalf("failed %v", err)
+		}
+		fd2, err := DecimalFromProto(&m2)
+		if err != nil || fd2.ToString(-1) != c.output {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v, err=%v", c.input, fd2.ToString(-1), c.output, err)
+		}
+	}
+	// round trip of maximum digits
+	fd, _ := DecimalFromAsciiString("-99999999999999999999999999999999999.999999999999999999999999999999")
+	m, _ := fd.ToProto()
+	if fd2, err := DecimalFromProto(&m); err != nil || fd2.Compare(&fd) != 0 {
+		t.Fatalf("failed %v %v", fd2.ToString(-1), err)
+	}
+	var inf FixedDecimal
+	inf.SetZero()
+	inf.setInf()
+	if _, err := inf.ToProto(); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestProtoDecimalUnmarshal(t *testing.T) {
+	type tcase struct {
+		input  string
+		output string
+		err    error
+	}
+	for _, c := range []tcase{
+		// negative scale
+		{"0a017b10feffffffffffffffff01", "12300", nil},
+		// unknown fields of all wire types are skipped
+		{"18011901020304050607082203616263250102030410020a017b", "1.23", nil},
+		// last field wins
+		{"0a010110030a017b", "0.123", nil},
+		{"0a", "", DecErrConversionSyntax},
+		{"0a05017b", "", DecErrConversionSyntax},
+		{"1202", "", DecErrConversionSyntax},
+		{"0f", "", DecErrConversionSyntax},
+		{"10ff", "", DecErrConversionSyntax},
+		// scale 31
+		{"0a0101101f", "", DecErrOverflow},
+		// scale 31 and 32 with trailing zeros
+		{"0a010a101f", "0.000000000000000000000000000001", nil},
+		{"0a019c1020", "-0.000000000000000000000000000001", nil},
+		{"1028", "0.000000000000000000000000000000", nil},
+		// 10^65
+		{"0a1c00f316271c7fc3908a8bef464e3945ef7a25360a0000000000000000", "", DecErrOverflow},
+	} {
+		bs, _ := hex.DecodeString(c.input)
+		var m ProtoDecimal
+		err := m.UnmarshalBinary(bs)
+		if err == nil {
+			var fd FixedDecimal
+			if err = fd.FromProto(&m); err == nil && fd.ToString(-1) != c.output {
+				t.Fatalf("decode %v mismatch: actual=%v, expected=%v", c.input, fd.ToString(-1), c.output)
+			}
+		}
+		if err != c.err {
+			t.Fatalf("decode %v mismatch: err=%v, expected=%v", c.input, err, c.err)
+		}
+	}
+}
+
+func TestDecimalProtoGoogle(t *testing.T) {
+	fd, _ := DecimalFromAsciiString("-12.340")
+	m, err := fd.ToProtoGoogle()
+	if err != nil || m.Value != "-12.340" {
+		t.Fatalf("failed %v %v", m.Value, err)
+	}
+	bs, _ := m.MarshalBinary()
+	if hex.EncodeToString(bs) != "0a072d31322e333430" {
+		t.Fatalf("encode mismatch: actual=%x", bs)
+	}
+	var m2 ProtoGoogleDecimal
+	if err = m2.UnmarshalBinary(bs); err != nil || m2.Value != "-12.340" {
+		t.Fatalf("failed %v %v", m2.Value, err)
+	}
+	for _, c := range [][2]string{
+		{"", "0"},
+		{"1.5E+3", "1500"},
+		{"+2.5e-1", "0.25"},
+		{".5", "0.5"},
+	} {
+		fd, err := DecimalFromProtoGoogle(&ProtoGoogleDecimal{Value: c[0]})
+		if err != nil || fd.ToString(-1) != c[1] {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v, err=%v", c[0], fd.ToString(-1), c[1], err)
+		}
+	}
+	for _, s := range []string{"NaN", "Infinity", "1.2.3", "abc"} {
+		if _, err := DecimalFromProtoGoogle(&ProtoGoogleDecimal{Value: s}); err != DecErrConversionSyntax {
+			t.Fatalf("decode %v mismatch: err=%v", s, err)
+		}
+	}
+	if err = m2.UnmarshalBinary([]byte{0x08, 0x01}); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+}
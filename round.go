@@ -40,6 +40,9 @@ func (fd *FixedDecimal) Round(frac int) {
 		}
 		// copy with offset
 		copy(fd.lsu[roundFracUnits-fracUnits:roundFracUnits+intgUnits], fd.lsu[:fracUnits+intgUnits])
+		for i := 0; i < roundFracUnits-fracUnits; i++ {
+			fd.lsu[i] = 0 // reset extended units to zero
+		}
 		fd.frac = int8(frac)
 		return
 	}
@@ -105,6 +108,12 @@ func (fd *FixedDecimal) RoundTo(result *FixedDecimal, frac int) {
 		}
 		// copy with offset
 		copy(result.lsu[roundFracUnits-fracUnits:roundFracUnits+intgUnits], fd.lsu[:fracUnits+intgUnits])
+		for i := 0; i < roundFracUnits-fracUnits; i++ {
+			result.lsu[i] = 0 // reset extended units to zero
+		}
+		for i := roundFracUnits + intgUnits; i < MaxUnits; i++ {
+			result.lsu[i] = 0 // reset higher units to zero
+		}
 		result.intg = fd.intg
 		result.frac = int8(frac)
 		return
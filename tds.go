@@ -0,0 +1,82 @@
+// SQL Server DECIMAL/NUMERIC in TDS protocol
+//
+// TDS stores DECIMAL(prec, scale) as a sign byte followed by the absolute
+// value of unscaled integer in little-endian, the width of integer depends
+// on precision:
+//
+//	| sign | magnitude (4, 8, 12 or 16 bytes) |
+//
+// sign is 1 if positive, 0 if negative.
+package fxd
+
+const (
+	TdsDecimalMaxPrec = 38
+
+	tdsDecimalPos = 1
+	tdsDecimalNeg = 0
+)
+
+// TdsDecimalLen returns byte length of TDS DECIMAL(prec, scale) value,
+// including the sign byte. Returns 0 if precision is invalid.
+func TdsDecimalLen(prec int) int {
+	switch {
+	case prec <= 0 || prec > TdsDecimalMaxPrec:
+		return 0
+	case prec <= 9:
+		return 5
+	case prec <= 19:
+		return 9
+	case prec <= 28:
+		return 13
+	default:
+		return 17
+	}
+}
+
+// AppendTdsDecimal appends this decimal as TDS DECIMAL(prec, scale) value
+// to given buffer, with byte length of TdsDecimalLen(prec).
+// Fractional digits beyond scale are rounded.
+func (fd *FixedDecimal) AppendTdsDecimal(buf []byte, prec, scale int) ([]byte, error) {
+	size := TdsDecimalLen(prec)
+	if size == 0 || scale < 0 || scale > prec {
+		return buf, DecErrInvalidType
+	}
+	var coef [DoubleMaxUnits]int32
+	if err := fd.unscaledUnits(coef[:], prec, scale); err != nil {
+		return buf, err
+	}
+	var words [2]uint64
+	unitsToWords(coef[:], words[:]) // 38 digits always fit 128 bits
+	var sign byte = tdsDecimalPos
+	if fd.IsNeg() && unitsNonZero(coef[:]) {
+		sign = tdsDecimalNeg
+	}
+	n := len(buf)
+	buf = append(buf, make([]byte, size)...)
+	buf[n] = sign
+	putWordsLE(buf[n+1:], words[:])
+	return buf, nil
+}
+
+// DecodeTdsDecimal creates a new decimal from TDS DECIMAL(prec, scale) value.
+// Returns DecErrOverflow if the value has more digits than prec.
+func DecodeTdsDecimal(bs []byte, prec, scale int) (FixedDecimal, error) {
+	var fd FixedDecimal
+	size := TdsDecimalLen(prec)
+	if size == 0 || scale < 0 || scale > prec {
+		return fd, DecErrInvalidType
+	}
+	if len(bs) != size || bs[0] > tdsDecimalPos {
+		return fd, DecErrConversionSyntax
+	}
+	var words [2]uint64
+	for i, b := range bs[1:] { // magnitude is unsigned
+		words[i/8] |= uint64(b) << (uint(i%8) * 8)
+	}
+	var coef [DoubleMaxUnits]int32
+	if !wordsToUnits(words[:], coef[:]) || unitsDigits(coef[:]) > prec {
+		return fd, DecErrOverflow
+	}
+	err := fd.setCoefUnits(coef[:], scale, bs[0] == tdsDecimalNeg)
+	return fd, err
+}
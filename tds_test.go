@@ -0,0 +1,62 @@
+package fxd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestTdsDecimalLen(t *testing.T) {
+	for _, c := range [][2]int{
+		{0, 0}, {1, 5}, {9, 5}, {10, 9}, {19, 9}, {20, 13}, {28, 13}, {29, 17}, {38, 17}, {39, 0},
+	} {
+		if actual := TdsDecimalLen(c[0]); actual != c[1] {
+			t.Fatalf("precision %v mismatch: actual=%v, expected=%v", c[0], actual, c[1])
+		}
+	}
+}
+
+func TestDecimalTdsDecimal(t *testing.T) {
+	type tcase struct {
+		input       string
+		prec, scale int
+		expected    string
+		output      string
+	}
+	for _, c := range []tcase{
+		{"0", 5, 2, "0100000000", "0.00"},
+		{"-0.001", 5, 2, "0100000000", "0.00"},
+		{"1.23", 5, 2, "017b000000", "1.23"},
+		{"-1.23", 5, 2, "007b000000", "-1.23"},
+		{"-1.235", 5, 2, "007c000000", "-1.24"},
+		{"9999999999999999999", 19, 0, "01ffffe7890423c78a", "9999999999999999999"},
+		{"12345678901234567890.12345678", 28, 8, "014ef338be917a796deb35fd03", "12345678901234567890.12345678"},
+		{"-99999999999999999999999999999999999999", 38, 0, "00ffffffff3f228a097ac4865aa84c3b4b", "-99999999999999999999999999999999999999"},
+	} {
+		fd, _ := DecimalFromAsciiString(c.input)
+		actual, err := fd.AppendTdsDecimal(nil, c.prec, c.scale)
+		if err != nil || hex.EncodeToString(actual) != c.expected {
+			t.Fatalf("encode %v mismatch: actual=%x, expected=%v, err=%v", c.input, actual, c.expected, err)
+		}
+		fd2, err := DecodeTdsDecimal(actual, c.prec, c.scale)
+		if err != nil || fd2.ToString(-1) != c.output {
+			t.Fatalf("decode %v mismatch: actual=%v, expected=%v, err=%v", c.input, fd2.ToString(-1), c.output, err)
+		}
+	}
+	fd, _ := DecimalFromAsciiString("1000")
+	if _, err := fd.AppendTdsDecimal(nil, 5, 2); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := fd.AppendTdsDecimal(nil, 39, 2); err != DecErrInvalidType {
+		t.Fatalf("failed %v", err)
+	}
+	// 10^9 exceeds precision 9
+	if _, err := DecodeTdsDecimal([]byte{0x01, 0x00, 0xca, 0x9a, 0x3b}, 9, 0); err != DecErrOverflow {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := DecodeTdsDecimal([]byte{0x02, 0x00, 0x00, 0x00, 0x00}, 9, 0); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+	if _, err := DecodeTdsDecimal([]byte{0x01, 0x00, 0x00, 0x00}, 9, 0); err != DecErrConversionSyntax {
+		t.Fatalf("failed %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+// Unary operations of fixed-point decimal
+//
+// Like Round and RoundTo, each operation has an in-place variant which
+// updates current decimal, and a To variant which stores result to
+// another decimal.
+// NaN is always propagated. Infinity is kept by Floor, Ceil and Trunc.
+package fxd
+
+// Neg negates this decimal in place.
+func (fd *FixedDecimal) Neg() {
+	fd.NegTo(fd)
+}
+
+// NegTo stores negated value of this decimal to result.
+// Zero is kept positive, NaN is unchanged.
+func (fd *FixedDecimal) NegTo(result *FixedDecimal) {
+	*result = *fd
+	if fd.IsNaN() || (!fd.IsInf() && fd.allUnitsZero()) {
+		return
+	}
+	if fd.IsNeg() {
+		result.setPos()
+	} else {
+		result.setNeg()
+	}
+}
+
+// Abs sets this decimal to its absolute value.
+func (fd *FixedDecimal) Abs() {
+	fd.AbsTo(fd)
+}
+
+// AbsTo stores absolute value of this decimal to result.
+func (fd *FixedDecimal) AbsTo(result *FixedDecimal) {
+	*result = *fd
+	result.setPos()
+}
+
+// Sign returns -1 if this decimal is negative, 1 if positive,
+// and 0 if zero or NaN.
+func (fd *FixedDecimal) Sign() int {
+	if fd.IsNaN() || (!fd.IsInf() && fd.allUnitsZero()) {
+		return 0
+	}
+	if fd.IsNeg() {
+		return -1
+	}
+	return 1
+}
+
+// CopySign sets sign of this decimal to sign of given decimal.
+func (fd *FixedDecimal) CopySign(sign *FixedDecimal) {
+	fd.CopySignTo(fd, sign)
+}
+
+// CopySignTo stores value with magnitude of this decimal and sign of
+// given decimal to result. Zero is kept positive.
+func (fd *FixedDecimal) CopySignTo(result, sign *FixedDecimal) {
+	neg := sign.IsNeg()
+	*result = *fd
+	result.setPos()
+	if neg && (fd.IsNaN() || fd.IsInf() || !fd.allUnitsZero()) {
+		result.setNeg()
+	}
+}
+
+// IsInteger returns true if this decimal is finite and has no non-zero
+// fractional digit.
+func (fd *FixedDecimal) IsInteger() bool {
+	if fd.IsNaN() || fd.IsInf() {
+		return false
+	}
+	return !unitsNonZero(fd.lsu[:fd.FracUnits()])
+}
+
+// Trunc truncates this decimal to frac fractional digits, a.k.a.
+// rounds toward zero.
+// frac can be negative to truncate the integral part.
+// As Round, if frac is larger than fractional digits of this decimal,
+// the fractional digits are extended, up to MaxFrac and MaxDigits in total.
+func (fd *FixedDecimal) Trunc(frac int) {
+	fd.TruncTo(fd, frac)
+}
+
+// TruncTo stores truncated value of this decimal to result.
+func (fd *FixedDecimal) TruncTo(result *FixedDecimal, frac int) {
+	if fd.IsNaN() || fd.IsInf() {
+		*result = *fd
+		return
+	}
+	if frac > MaxFrac {
+		frac = MaxFrac
+	}
+	if thisFrac := int(fd.Frac()); frac > thisFrac {
+		// extended fractional digits are limited by integral digits
+		fracUnits := fd.FracUnits()
+		intg := unitsDigits(fd.lsu[fracUnits : fracUnits+fd.IntgUnits()])
+		frac = maxInt(thisFrac, minInt(frac, MaxDigits-intg))
+	}
+	scale := frac
+	if scale < 0 {
+		scale = 0
+	}
+	var coef [DoubleMaxUnits]int32
+	fd.coefUnits(coef[:], scale)
+	if frac < 0 {
+		coefDivPow10(coef[:], -frac)
+		coefMulPow10(coef[:], -frac)
+	}
+	coefMulPow10(coef[:], getUnits(scale)*DigitsPerUnit-scale)
+	result.setAlignedUnits(coef[:MaxUnits], scale, fd.IsNeg())
+}
+
+// Floor sets this decimal to the greatest integer less than or equal to it.
+func (fd *FixedDecimal) Floor() {
+	fd.FloorTo(fd)
+}
+
+// FloorTo stores the greatest integer less than or equal to this decimal
+// to result.
+func (fd *FixedDecimal) FloorTo(result *FixedDecimal) {
+	fd.integralTo(result, fd.IsNeg())
+}
+
+// Ceil sets this decimal to the least integer greater than or equal to it.
+func (fd *FixedDecimal) Ceil() {
+	fd.CeilTo(fd)
+}
+
+// CeilTo stores the least integer greater than or equal to this decimal
+// to result.
+func (fd *FixedDecimal) CeilTo(result *FixedDecimal) {
+	fd.integralTo(result, !fd.IsNeg())
+}
+
+// integralTo stores integral part of this decimal to result,
+// rounding away from zero if up is true, otherwise toward zero.
+func (fd *FixedDecimal) integralTo(result *FixedDecimal, up bool) {
+	if fd.IsNaN() || fd.IsInf() {
+		*result = *fd
+		return
+	}
+	var coef [DoubleMaxUnits]int32
+	fd.coefUnits(coef[:], 0)
+	if up && unitsNonZero(fd.lsu[:fd.FracUnits()]) {
+		// integral units are less than MaxUnits if there is fractional part,
+		// so the carry always fits units. Integral digits are less than
+		// MaxDigits as well, so the carry fits MaxDigits unless this decimal
+		// already exceeds it
+		unitsMulAdd(coef[:], 1, 1)
+	}
+	result.setAlignedUnits(coef[:MaxUnits], 0, fd.IsNeg())
+}
+
+// Modf splits this decimal into integral and fractional parts,
+// both have the same sign as this decimal.
+// Modf(±Inf) returns ±Inf and NaN, Modf(NaN) returns NaN and NaN.
+func (fd *FixedDecimal) Modf() (intPart, fracPart FixedDecimal) {
+	fd.ModfTo(&intPart, &fracPart)
+	return
+}
+
+// ModfTo stores integral and fractional parts of this decimal to
+// intPart and fracPart.
+func (fd *FixedDecimal) ModfTo(intPart, fracPart *FixedDecimal) {
+	src := *fd // intPart or fracPart may be this decimal
+	if src.IsNaN() || src.IsInf() {
+		*intPart = src
+		fracPart.SetZero()
+		fracPart.setNaN()
+		return
+	}
+	src.TruncTo(intPart, 0)
+	var coef [MaxUnits]int32
+	copy(coef[:], src.lsu[:src.FracUnits()])
+	fracPart.setAlignedUnits(coef[:], int(src.Frac()), src.IsNeg())
+}
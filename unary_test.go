@@ -0,0 +1,202 @@
+package fxd
+
+import "testing"
+
+func TestDecimalNegAbsSign(t *testing.T) {
+	type tcase struct {
+		input string
+		neg   string
+		abs   string
+		sign  int
+	}
+	for _, c := range []tcase{
+		{"0", "0", "0", 0},
+		{"0.00", "0.00", "0.00", 0},
+		{"1.5", "-1.5", "1.5", 1},
+		{"-1.5", "1.5", "1.5", -1},
+		{"Inf", "-Inf", "Inf", 1},
+		{"-Inf", "Inf", "Inf", -1},
+	} {
+		fd := parseSpecial(c.input)
+		var neg, abs FixedDecimal
+		fd.NegTo(&neg)
+		fd.AbsTo(&abs)
+		if specialString(&neg) != c.neg || specialString(&abs) != c.abs || fd.Sign() != c.sign {
+			t.Fatalf("%v mismatch: neg=%v, abs=%v, sign=%v", c.input, specialString(&neg), specialString(&abs), fd.Sign())
+		}
+		fd.Neg()
+		if fd != neg {
+			t.Fatalf("%v in-place neg mismatch: actual=%v", c.input, specialString(&fd))
+		}
+		fd = parseSpecial(c.input)
+		fd.Abs()
+		if fd != abs {
+			t.Fatalf("%v in-place abs mismatch: actual=%v", c.input, specialString(&fd))
+		}
+		fd = parseSpecial(c.input)
+		DecimalNeg(&fd)
+		if fd != neg {
+			t.Fatalf("%v DecimalNeg mismatch: actual=%v", c.input, specialString(&fd))
+		}
+	}
+	nan := parseSpecial("NaN")
+	nan.Neg()
+	if !nan.IsNaN() || nan.IsNeg() || nan.Sign() != 0 {
+		t.Fatal("failed")
+	}
+}
+
+func TestDecimalCopySign(t *testing.T) {
+	type tcase struct {
+		input, sign, expected string
+	}
+	for _, c := range []tcase{
+		{"1.5", "-1", "-1.5"},
+		{"-1.5", "1", "1.5"},
+		{"-1.5", "-0.1", "-1.5"},
+		{"0", "-1", "0"},
+		{"Inf", "-1", "-Inf"},
+		{"-Inf", "Inf", "Inf"},
+		{"2", "-Inf", "-2"},
+	} {
+		fd, sign := parseSpecial(c.input), parseSpecial(c.sign)
+		var res FixedDecimal
+		fd.CopySignTo(&res, &sign)
+		if specialString(&res) != c.expected {
+			t.Fatalf("copysign %v %v mismatch: actual=%v, expected=%v", c.input, c.sign, specialString(&res), c.expected)
+		}
+		fd.CopySign(&sign)
+		if fd != res {
+			t.Fatalf("copysign %v %v in-place mismatch: actual=%v", c.input, c.sign, specialString(&fd))
+		}
+	}
+}
+
+func TestDecimalFloorCeil(t *testing.T) {
+	type tcase struct {
+		input, floor, ceil string
+	}
+	for _, c := range []tcase{
+		{"0", "0", "0"},
+		{"1", "1", "1"},
+		{"1.000", "1", "1"},
+		{"1.5", "1", "2"},
+		{"-1.5", "-2", "-1"},
+		{"-0.5", "-1", "0"},
+		{"0.5", "0", "1"},
+		{"999999999.000000001", "999999999", "1000000000"},
+		{"-999999999.000000001", "-1000000000", "-999999999"},
+		{"0.000000000000000000000000000001", "0", "1"},
+		// integral digits reach MaxDigits after carry
+		{"9999999999999999999999999999999999999999999999999999999999999999.5", "9999999999999999999999999999999999999999999999999999999999999999", "10000000000000000000000000000000000000000000000000000000000000000"},
+		{"-9999999999999999999999999999999999999999999999999999999999999999.5", "-10000000000000000000000000000000000000000000000000000000000000000", "-9999999999999999999999999999999999999999999999999999999999999999"},
+		{"Inf", "Inf", "Inf"},
+		{"-Inf", "-Inf", "-Inf"},
+		{"NaN", "NaN", "NaN"},
+	} {
+		fd := parseSpecial(c.input)
+		var floor, ceil FixedDecimal
+		fd.FloorTo(&floor)
+		fd.CeilTo(&ceil)
+		if specialString(&floor) != c.floor || specialString(&ceil) != c.ceil {
+			t.Fatalf("%v mismatch: floor=%v, ceil=%v", c.input, specialString(&floor), specialString(&ceil))
+		}
+		fd.Floor()
+		if fd != floor {
+			t.Fatalf("%v in-place floor mismatch: actual=%v", c.input, specialString(&fd))
+		}
+		fd = parseSpecial(c.input)
+		fd.Ceil()
+		if fd != ceil {
+			t.Fatalf("%v in-place ceil mismatch: actual=%v", c.input, specialString(&fd))
+		}
+	}
+}
+
+func TestDecimalTrunc(t *testing.T) {
+	type tcase struct {
+		input    string
+		frac     int
+		expected string
+	}
+	for _, c := range []tcase{
+		{"1.259", 2, "1.25"},
+		{"-1.259", 2, "-1.25"},
+		{"1.259", 0, "1"},
+		{"-0.259", 0, "0"},
+		{"1.25", 3, "1.250"},
+		{"1.5", 10, "1.5000000000"},
+		{"123456789.123456789123", 9, "123456789.123456789"},
+		{"123456789.123456789123", 10, "123456789.1234567891"},
+		{"1259.5", -1, "1250"},
+		{"-1259.5", -2, "-1200"},
+		{"1259", -4, "0"},
+		{"1234567890123", -10, "1230000000000"},
+		{"1.5", 40, "1.500000000000000000000000000000"},
+		// extended fractional digits are limited by MaxDigits
+		{"1234567890123456789012345678901234567890123456", 30, "1234567890123456789012345678901234567890123456.0000000000000000000"},
+		{"-99999999999999999999999999999999999999999999999999999999999999999", 1, "-99999999999999999999999999999999999999999999999999999999999999999"},
+		{"99999999999999999999999999999999999.5", 31, "99999999999999999999999999999999999.500000000000000000000000000000"},
+		{"Inf", 2, "Inf"},
+		{"NaN", 2, "NaN"},
+	} {
+		fd := parseSpecial(c.input)
+		var res FixedDecimal
+		fd.TruncTo(&res, c.frac)
+		if specialString(&res) != c.expected {
+			t.Fatalf("trunc %v %v mismatch: actual=%v, expected=%v", c.input, c.frac, specialString(&res), c.expected)
+		}
+		fd.Trunc(c.frac)
+		if specialString(&fd) != c.expected {
+			t.Fatalf("trunc %v %v in-place mismatch: actual=%v", c.input, c.frac, specialString(&fd))
+		}
+	}
+}
+
+func TestDecimalModf(t *testing.T) {
+	type tcase struct {
+		input, intPart, fracPart string
+		isInt                    bool
+	}
+	for _, c := range []tcase{
+		{"0", "0", "0", true},
+		{"3", "3", "0", true},
+		{"3.000", "3", "0.000", true},
+		{"3.25", "3", "0.25", false},
+		{"-3.25", "-3", "-0.25", false},
+		{"-0.25", "0", "-0.25", false},
+		{"1000000000.000000001", "1000000000", "0.000000001", false},
+		{"Inf", "Inf", "NaN", false},
+		{"-Inf", "-Inf", "NaN", false},
+		{"NaN", "NaN", "NaN", false},
+	} {
+		fd := parseSpecial(c.input)
+		ip, fp := fd.Modf()
+		if specialString(&ip) != c.intPart || specialString(&fp) != c.fracPart {
+			t.Fatalf("modf %v mismatch: actual=%v,%v, expected=%v,%v", c.input, specialString(&ip), specialString(&fp), c.intPart, c.fracPart)
+		}
+		if fd.IsInteger() != c.isInt {
+			t.Fatalf("isInteger %v mismatch: actual=%v", c.input, fd.IsInteger())
+		}
+		// result aliases input
+		other := fd
+		fd.ModfTo(&fd, &other)
+		if fd != ip || other != fp {
+			t.Fatalf("modf %v aliasing mismatch: actual=%v,%v", c.input, specialString(&fd), specialString(&other))
+		}
+	}
+}
+
+// specialString formats decimal including NaN and negative infinity.
+func specialString(fd *FixedDecimal) string {
+	if fd.IsInf() {
+		if fd.IsNeg() {
+			return "-Inf"
+		}
+		return "Inf"
+	}
+	if fd.IsNaN() {
+		return "NaN"
+	}
+	return fd.ToString(-1)
+}
@@ -1,5 +1,7 @@
 package fxd
 
+import "math/bits"
+
 const DivIncrFrac = 4
 
 type DecStatus uint32
@@ -27,6 +29,10 @@ const (
 	DecErrConversionSyntax DecErr = iota
 	DecErrOverflow
 	DecErrDivisionByZero
+	DecErrInvalidType
+	DecErrInvalidValue
+	DecErrInvalidDigit
+	DecErrCurrencyMismatch
 )
 
 func (e DecErr) Error() string {
@@ -37,6 +43,14 @@ func (e DecErr) Error() string {
 		return "decimal overflow"
 	case DecErrDivisionByZero:
 		return "decimal division by 0"
+	case DecErrInvalidType:
+		return "decimal invalid type"
+	case DecErrInvalidValue:
+		return "decimal invalid value"
+	case DecErrInvalidDigit:
+		return "decimal invalid digit or sign"
+	case DecErrCurrencyMismatch:
+		return "decimal currency mismatch"
 	default:
 		return "decimal unknown error"
 	}
@@ -445,3 +459,349 @@ func assertTrue(val bool, stmt string) {
 		panic(stmt)
 	}
 }
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// unitsMulAdd computes lsu*m+a in place on little-endian units,
+// returns the carry out of most significant unit.
+func unitsMulAdd(lsu []int32, m int64, a int64) int64 {
+	carry := a
+	for i, v := range lsu {
+		x := int64(v)*m + carry
+		carry = x / Unit
+		lsu[i] = int32(x - carry*Unit)
+	}
+	return carry
+}
+
+// unitsDivRem divides little-endian units by d in place,
+// returns the remainder.
+func unitsDivRem(lsu []int32, d int64) int64 {
+	var rem int64
+	for i := len(lsu) - 1; i >= 0; i-- {
+		x := rem*Unit + int64(lsu[i])
+		q := x / d
+		rem = x - q*d
+		lsu[i] = int32(q)
+	}
+	return rem
+}
+
+// unitsDivRem64 divides little-endian units by d in place,
+// returns the remainder. Unlike unitsDivRem, d can be any non-zero value.
+func unitsDivRem64(lsu []int32, d uint64) uint64 {
+	var rem uint64
+	for i := len(lsu) - 1; i >= 0; i-- {
+		hi, lo := bits.Mul64(rem, Unit)
+		var c uint64
+		lo, c = bits.Add64(lo, uint64(lsu[i]), 0)
+		q, r := bits.Div64(hi+c, lo, d)
+		lsu[i] = int32(q)
+		rem = r
+	}
+	return rem
+}
+
+// unitsDivMod divides little-endian units u by v with Knuth's algorithm D,
+// stores quotient to q and remainder to u.
+// v must be non-zero and have at most DoubleMaxUnits units, u must have at
+// most 2*DoubleMaxUnits units, and q must have at least len(u) units.
+func unitsDivMod(u []int32, v []int32, q []int32) {
+	for i := range q {
+		q[i] = 0
+	}
+	n := len(v)
+	for n > 0 && v[n-1] == 0 {
+		n--
+	}
+	if n == 1 { // short division
+		copy(q, u)
+		rem := unitsDivRem(q[:len(u)], int64(v[0]))
+		for i := range u {
+			u[i] = 0
+		}
+		u[0] = int32(rem)
+		return
+	}
+	m := len(u)
+	for m > 0 && u[m-1] == 0 {
+		m--
+	}
+	if m < n { // quotient is zero
+		return
+	}
+	// D1. normalization, with one extra leading unit of dividend
+	norm := int64(Unit / (v[n-1] + 1))
+	var un [2*DoubleMaxUnits + 1]int32
+	var vn [DoubleMaxUnits]int32
+	copy(un[:m], u[:m])
+	un[m] = int32(unitsMulAdd(un[:m], norm, 0))
+	copy(vn[:n], v[:n])
+	unitsMulAdd(vn[:n], norm, 0)
+	vd0, vd1 := int64(vn[n-1]), int64(vn[n-2])
+	for j := m - n; j >= 0; j-- {
+		// D3. make the guess on leading two units
+		x := int64(un[j+n])*Unit + int64(un[j+n-1])
+		qhat := x / vd0
+		rhat := x - qhat*vd0
+		if qhat >= Unit {
+			qhat = Unit - 1
+			rhat = x - qhat*vd0
+		}
+		// rhat no less than Unit means qhat already satisfies next unit
+		for rhat < Unit && qhat*vd1 > rhat*Unit+int64(un[j+n-2]) {
+			qhat--
+			rhat += vd0
+		}
+		// D4. multiply and subtract
+		var carry, borrow int64
+		for i := 0; i < n; i++ {
+			p := qhat*int64(vn[i]) + carry
+			carry = p / Unit
+			r := int64(un[i+j]) - (p - carry*Unit) + borrow
+			borrow = 0
+			if r < 0 {
+				r += Unit
+				borrow = -1
+			}
+			un[i+j] = int32(r)
+		}
+		top := int64(un[j+n]) - carry + borrow
+		// D6. add back if the guess is one larger
+		if top < 0 {
+			qhat--
+			var c int32
+			for i := 0; i < n; i++ {
+				un[i+j], c = addWithCarry(un[i+j], vn[i], c)
+			}
+			top += int64(c)
+		}
+		un[j+n] = int32(top)
+		q[j] = int32(qhat)
+	}
+	// D8. unnormalize remainder
+	for i := range u {
+		u[i] = 0
+	}
+	copy(u, un[:n])
+	unitsDivRem(u[:n], norm)
+}
+
+// unitsAdd adds little-endian units v to acc in place.
+// Returns false if the carry cannot be stored.
+func unitsAdd(acc []int32, v []int32) bool {
+	var carry int32
+	i := 0
+	for ; i < len(v); i++ {
+		acc[i], carry = addWithCarry(acc[i], v[i], carry)
+	}
+	for ; carry != 0 && i < len(acc); i++ {
+		acc[i], carry = addWithCarry(acc[i], 0, carry)
+	}
+	return carry == 0
+}
+
+// unitsSub subtracts little-endian units v from acc in place.
+// acc must be greater than or equal to v.
+func unitsSub(acc []int32, v []int32) {
+	var borrow int32
+	i := 0
+	for ; i < len(v); i++ {
+		acc[i], borrow = subWithBorrow(acc[i], v[i], borrow)
+	}
+	for ; borrow != 0 && i < len(acc); i++ {
+		acc[i], borrow = subWithBorrow(acc[i], 0, borrow)
+	}
+}
+
+// unitsCmp compares two little-endian units of any length.
+func unitsCmp(lhs []int32, rhs []int32) int {
+	for i := maxInt(len(lhs), len(rhs)) - 1; i >= 0; i-- {
+		var l, r int32
+		if i < len(lhs) {
+			l = lhs[i]
+		}
+		if i < len(rhs) {
+			r = rhs[i]
+		}
+		if l != r {
+			if l > r {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// unitsMul stores product of little-endian units lhs and rhs to result,
+// result must have at least len(lhs)+len(rhs) units.
+func unitsMul(result []int32, lhs []int32, rhs []int32) {
+	for i := range result {
+		result[i] = 0
+	}
+	for j, rv := range rhs {
+		if rv == 0 {
+			continue
+		}
+		var carry int64
+		for i, lv := range lhs {
+			v := int64(lv)*int64(rv) + int64(result[i+j]) + carry
+			carry = v / Unit
+			result[i+j] = int32(v - carry*Unit)
+		}
+		result[j+len(lhs)] = int32(carry)
+	}
+}
+
+// unitsDigits returns number of digits of little-endian units
+// without leading zeros.
+func unitsDigits(lsu []int32) int {
+	for i := len(lsu) - 1; i >= 0; i-- {
+		if lsu[i] != 0 {
+			return i*DigitsPerUnit + DigitsPerUnit - unitLeadingZeroes(lsu[i])
+		}
+	}
+	return 0
+}
+
+// unitsToWords converts little-endian base-1e9 units to little-endian
+// base-2^64 words. Returns false if words cannot hold the value.
+func unitsToWords(lsu []int32, words []uint64) bool {
+	for i := range words {
+		words[i] = 0
+	}
+	for i := len(lsu) - 1; i >= 0; i-- {
+		carry := uint64(lsu[i])
+		for j, w := range words {
+			hi, lo := bits.Mul64(w, Unit)
+			var c uint64
+			words[j], c = bits.Add64(lo, carry, 0)
+			carry = hi + c
+		}
+		if carry != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// wordsToUnits converts little-endian base-2^64 words to little-endian
+// base-1e9 units. words will be modified.
+// Returns false if units cannot hold the value.
+func wordsToUnits(words []uint64, lsu []int32) bool {
+	for i := range lsu {
+		lsu[i] = 0
+	}
+	n := len(words)
+	for i := 0; ; i++ {
+		for n > 0 && words[n-1] == 0 {
+			n--
+		}
+		if n == 0 {
+			return true
+		}
+		if i == len(lsu) {
+			return false
+		}
+		var rem uint64
+		for j := n - 1; j >= 0; j-- {
+			words[j], rem = bits.Div64(rem, words[j], Unit)
+		}
+		lsu[i] = int32(rem)
+	}
+}
+
+// wordsNeg negates little-endian words as two's complement integer in place.
+func wordsNeg(words []uint64) {
+	var borrow uint64
+	for i, w := range words {
+		words[i], borrow = bits.Sub64(0, w, borrow)
+	}
+}
+
+// putWordsLE writes little-endian words to bs in little-endian byte order.
+func putWordsLE(bs []byte, words []uint64) {
+	for i := range bs {
+		bs[i] = byte(words[i/8] >> (uint(i%8) * 8))
+	}
+}
+
+// getWordsLE reads little-endian bytes into words, with sign extension.
+func getWordsLE(bs []byte, words []uint64) {
+	var ext uint64
+	if len(bs) > 0 && bs[len(bs)-1]&0x80 != 0 {
+		ext = ^uint64(0)
+	}
+	for i := range words {
+		words[i] = ext
+	}
+	for i, b := range bs {
+		shift := uint(i%8) * 8
+		words[i/8] = words[i/8]&^(0xff<<shift) | uint64(b)<<shift
+	}
+}
+
+// putWordsBE writes little-endian words to bs in big-endian byte order.
+// If bs is longer than words, the value is sign extended.
+func putWordsBE(bs []byte, words []uint64) {
+	var ext byte
+	if int64(words[len(words)-1]) < 0 {
+		ext = 0xff
+	}
+	for i, j := len(bs)-1, 0; i >= 0; i, j = i-1, j+1 {
+		if j < len(words)*8 {
+			bs[i] = byte(words[j/8] >> (uint(j%8) * 8))
+		} else {
+			bs[i] = ext
+		}
+	}
+}
+
+// getWordsBE reads big-endian bytes into little-endian words, with sign extension.
+// Returns false if the value cannot be held by words.
+func getWordsBE(bs []byte, words []uint64) bool {
+	var ext uint64
+	if len(bs) > 0 && bs[0]&0x80 != 0 {
+		ext = ^uint64(0)
+	}
+	for i := range words {
+		words[i] = ext
+	}
+	for i, j := len(bs)-1, 0; i >= 0; i, j = i-1, j+1 {
+		if j < len(words)*8 {
+			shift := uint(j%8) * 8
+			words[j/8] = words[j/8]&^(0xff<<shift) | uint64(bs[i])<<shift
+		} else if bs[i] != byte(ext) { // must be sign extension
+			return false
+		}
+	}
+	// sign bit must be consistent with extension
+	return len(bs) <= len(words)*8 || (int64(words[len(words)-1]) < 0) == (ext != 0)
+}
+
+// minTwosComplementLen returns minimal number of bytes to store
+// little-endian words as two's complement integer.
+func minTwosComplementLen(words []uint64) int {
+	var ext uint64
+	if int64(words[len(words)-1]) < 0 {
+		ext = ^uint64(0)
+	}
+	n := len(words) * 8
+	for n > 1 {
+		j := n - 1 // index of most significant byte
+		b := byte(words[j/8] >> (uint(j%8) * 8))
+		next := byte(words[(j-1)/8] >> (uint((j-1)%8) * 8))
+		if b != byte(ext) || (next&0x80 != 0) != (ext != 0) {
+			break
+		}
+		n--
+	}
+	return n
+}
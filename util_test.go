@@ -31,3 +31,30 @@ func TestUnitsGreaterEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestUnitsDivMod(t *testing.T) {
+	type tcase struct {
+		u, v []int32
+	}
+	for i, c := range []tcase{
+		{[]int32{7}, []int32{3}},
+		{[]int32{0, 0, 1}, []int32{1, 1}},
+		{[]int32{999999999, 999999999, 999999999, 999999999}, []int32{999999999, 999999999}},
+		{[]int32{0, 0, 0, 500000000}, []int32{1, 500000000}},
+		{[]int32{123456789, 987654321, 5}, []int32{0, 0, 0, 1}},
+		{[]int32{1, 0, 0, 0, 0, 1}, []int32{999999999, 0, 1}},
+		{[]int32{0, 999999999, 999999999, 0}, []int32{1, 999999999}},
+	} {
+		u := make([]int32, len(c.u))
+		copy(u, c.u)
+		q := make([]int32, len(u))
+		unitsDivMod(u, c.v, q)
+		// q*v+r equals to dividend, and r is less than divisor
+		res := make([]int32, len(q)+len(c.v))
+		unitsMul(res, q, c.v)
+		unitsAdd(res, u)
+		if unitsCmp(res, c.u) != 0 || unitsCmp(u, c.v) >= 0 {
+			t.Fatalf("case %v mismatch: q=%v, r=%v", i, q, u)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+// Vectorized arithmetic and comparison over decimal columns
+//
+// A column is a slice of decimals. An optional selection vector lists
+// the row indexes to evaluate, other rows of output are left untouched.
+// If all values of a column share the same intg and frac, which is the
+// usual case of a table column, unit numbers are computed once for the
+// whole column instead of once per row.
+//
+// Arithmetic stops at the first failing row and returns *VecError with
+// its index, output of rows before it is already written.
+// Comparison stores results to a bitmap in Arrow layout, i.e. bit i%8 of
+// byte i/8 is set if row i satisfies the predicate.
+package fxd
+
+import (
+	"math/bits"
+	"strconv"
+)
+
+// VecError is returned by vectorized arithmetic with index of the
+// first failing row.
+type VecError struct {
+	Row int
+	Err error
+}
+
+func (e *VecError) Error() string {
+	return "row " + strconv.Itoa(e.Row) + ": " + e.Err.Error()
+}
+
+// Unwrap returns error of the failing row.
+func (e *VecError) Unwrap() error {
+	return e.Err
+}
+
+// CmpOp is comparison operator of vectorized comparison.
+type CmpOp uint8
+
+const (
+	CmpEq CmpOp = iota
+	CmpNe
+	CmpLt
+	CmpLe
+	CmpGt
+	CmpGe
+)
+
+// AddVec computes lhs[i]+rhs[i] and stores to out[i].
+func AddVec(lhs, rhs, out []FixedDecimal, sel []int) error {
+	return arithVec(vecAdd, lhs, rhs, nil, nil, out, sel, 0)
+}
+
+// SubVec computes lhs[i]-rhs[i] and stores to out[i].
+func SubVec(lhs, rhs, out []FixedDecimal, sel []int) error {
+	return arithVec(vecSub, lhs, rhs, nil, nil, out, sel, 0)
+}
+
+// MulVec computes lhs[i]*rhs[i] and stores to out[i].
+func MulVec(lhs, rhs, out []FixedDecimal, sel []int) error {
+	return arithVec(vecMul, lhs, rhs, nil, nil, out, sel, 0)
+}
+
+// DivVec computes lhs[i]/rhs[i] and stores to out[i].
+// incrFrac has same meaning as DecimalDiv.
+func DivVec(lhs, rhs, out []FixedDecimal, sel []int, incrFrac int) error {
+	return arithVec(vecDiv, lhs, rhs, nil, nil, out, sel, incrFrac)
+}
+
+// ModVec computes lhs[i]%rhs[i] and stores to out[i].
+func ModVec(lhs, rhs, out []FixedDecimal, sel []int) error {
+	return arithVec(vecMod, lhs, rhs, nil, nil, out, sel, 0)
+}
+
+// AddVecScalar computes lhs[i]+rhs and stores to out[i].
+func AddVecScalar(lhs []FixedDecimal, rhs *FixedDecimal, out []FixedDecimal, sel []int) error {
+	return arithVec(vecAdd, lhs, nil, nil, rhs, out, sel, 0)
+}
+
+// SubVecScalar computes lhs[i]-rhs and stores to out[i].
+func SubVecScalar(lhs []FixedDecimal, rhs *FixedDecimal, out []FixedDecimal, sel []int) error {
+	return arithVec(vecSub, lhs, nil, nil, rhs, out, sel, 0)
+}
+
+// SubScalarVec computes lhs-rhs[i] and stores to out[i].
+func SubScalarVec(lhs *FixedDecimal, rhs, out []FixedDecimal, sel []int) error {
+	return arithVec(vecSub, nil, rhs, lhs, nil, out, sel, 0)
+}
+
+// MulVecScalar computes lhs[i]*rhs and stores to out[i].
+func MulVecScalar(lhs []FixedDecimal, rhs *FixedDecimal, out []FixedDecimal, sel []int) error {
+	return arithVec(vecMul, lhs, nil, nil, rhs, out, sel, 0)
+}
+
+// DivVecScalar computes lhs[i]/rhs and stores to out[i].
+// Division by zero is reported on the first evaluated row.
+func DivVecScalar(lhs []FixedDecimal, rhs *FixedDecimal, out []FixedDecimal, sel []int, incrFrac int) error {
+	return arithVec(vecDiv, lhs, nil, nil, rhs, out, sel, incrFrac)
+}
+
+// DivScalarVec computes lhs/rhs[i] and stores to out[i].
+func DivScalarVec(lhs *FixedDecimal, rhs, out []FixedDecimal, sel []int, incrFrac int) error {
+	return arithVec(vecDiv, nil, rhs, lhs, nil, out, sel, incrFrac)
+}
+
+// ModVecScalar computes lhs[i]%rhs and stores to out[i].
+func ModVecScalar(lhs []FixedDecimal, rhs *FixedDecimal, out []FixedDecimal, sel []int) error {
+	return arithVec(vecMod, lhs, nil, nil, rhs, out, sel, 0)
+}
+
+// CompareVec sets bit i of bitmap if lhs[i] op rhs[i] holds.
+// Special values are compared as CompareAny, NaN only satisfies CmpNe.
+// Bits of rows not in selection vector are cleared.
+// bitmap must have at least (len(lhs)+7)/8 bytes.
+func CompareVec(lhs, rhs []FixedDecimal, op CmpOp, bitmap []byte, sel []int) error {
+	return compareVec(lhs, rhs, nil, op, bitmap, sel)
+}
+
+// CompareVecScalar sets bit i of bitmap if lhs[i] op rhs holds.
+func CompareVecScalar(lhs []FixedDecimal, rhs *FixedDecimal, op CmpOp, bitmap []byte, sel []int) error {
+	return compareVec(lhs, nil, rhs, op, bitmap, sel)
+}
+
+// BitmapToSel appends indexes of set bits among the first n bits
+// of bitmap to sel, so the result can be used as selection vector
+// of following operations.
+func BitmapToSel(sel []int, bitmap []byte, n int) []int {
+	for i := 0; i < n; i += 8 {
+		b := bitmap[i/8]
+		for b != 0 {
+			j := i + bits.TrailingZeros8(b)
+			if j >= n {
+				break
+			}
+			sel = append(sel, j)
+			b &= b - 1
+		}
+	}
+	return sel
+}
+
+type vecOp uint8
+
+const (
+	vecAdd vecOp = iota
+	vecSub
+	vecMul
+	vecDiv
+	vecMod
+)
+
+// vecLayout is the common layout of all evaluated values of a column.
+type vecLayout struct {
+	uniform bool
+	iu, fu  int
+}
+
+// columnLayout checks whether all evaluated values of column are finite
+// and share the same intg and frac.
+// Operand with non-nil scalar is not a column.
+func columnLayout(col []FixedDecimal, scalar *FixedDecimal, sel []int) vecLayout {
+	if scalar != nil {
+		return valueLayout(scalar)
+	}
+	first := -1
+	if sel == nil {
+		if len(col) > 0 {
+			first = 0
+		}
+	} else if len(sel) > 0 {
+		first = sel[0]
+	}
+	if first < 0 {
+		return vecLayout{}
+	}
+	intg, frac := col[first].intg&0x7f, col[first].frac
+	if sel == nil {
+		for i := range col {
+			if col[i].intg&0x7f != intg || col[i].frac != frac {
+				return vecLayout{}
+			}
+		}
+	} else {
+		for _, i := range sel {
+			if col[i].intg&0x7f != intg || col[i].frac != frac {
+				return vecLayout{}
+			}
+		}
+	}
+	return valueLayout(&col[first])
+}
+
+func valueLayout(fd *FixedDecimal) vecLayout {
+	if fd.IsNaN() || fd.IsInf() {
+		return vecLayout{}
+	}
+	return vecLayout{uniform: true, iu: fd.IntgUnits(), fu: fd.FracUnits()}
+}
+
+// vecRows returns number of rows of operands, or -1 if lengths mismatch.
+// Operand with non-nil scalar is not a column.
+func vecRows(lhs, rhs []FixedDecimal, lscalar, rscalar *FixedDecimal) int {
+	if lscalar != nil {
+		return len(rhs)
+	}
+	if rscalar != nil || len(lhs) == len(rhs) {
+		return len(lhs)
+	}
+	return -1
+}
+
+func arithVec(op vecOp, lhs, rhs []FixedDecimal, lscalar, rscalar *FixedDecimal, out []FixedDecimal, sel []int, incrFrac int) error {
+	n := vecRows(lhs, rhs, lscalar, rscalar)
+	if n < 0 || len(out) != n {
+		return DecErrInvalidValue
+	}
+	// scalar may be element of output
+	var lbuf, rbuf FixedDecimal
+	if lscalar != nil {
+		lbuf = *lscalar
+		lscalar = &lbuf
+	}
+	if rscalar != nil {
+		rbuf = *rscalar
+		rscalar = &rbuf
+	}
+	llay := columnLayout(lhs, lscalar, sel)
+	rlay := columnLayout(rhs, rscalar, sel)
+	uniform := llay.uniform && rlay.uniform
+	if sel == nil {
+		for i := 0; i < n; i++ {
+			l, r := vecOperand(lhs, lscalar, i), vecOperand(rhs, rscalar, i)
+			if err := arithRow(op, l, r, &out[i], uniform, &llay, &rlay, incrFrac); err != nil {
+				return &VecError{Row: i, Err: err}
+			}
+		}
+		return nil
+	}
+	for _, i := range sel {
+		l, r := vecOperand(lhs, lscalar, i), vecOperand(rhs, rscalar, i)
+		if err := arithRow(op, l, r, &out[i], uniform, &llay, &rlay, incrFrac); err != nil {
+			return &VecError{Row: i, Err: err}
+		}
+	}
+	return nil
+}
+
+func vecOperand(col []FixedDecimal, scalar *FixedDecimal, i int) *FixedDecimal {
+	if scalar != nil {
+		return scalar
+	}
+	return &col[i]
+}
+
+// arithRow evaluates single row. If uniform is true, precomputed unit
+// numbers are passed to the underlying algorithm directly.
+func arithRow(op vecOp, lhs, rhs, result *FixedDecimal, uniform bool, llay, rlay *vecLayout, incrFrac int) error {
+	switch op {
+	case vecAdd, vecSub:
+		if !uniform || lhs.IsZero() || rhs.IsZero() {
+			if op == vecAdd {
+				return DecimalAdd(lhs, rhs, result)
+			}
+			return DecimalSub(lhs, rhs, result)
+		}
+		lneg := lhs.IsNeg()
+		rneg := rhs.IsNeg() != (op == vecSub)
+		if lneg == rneg {
+			if err := addAbsUnits(lhs, rhs, result, llay.iu, llay.fu, rlay.iu, rlay.fu); err != nil {
+				return err
+			}
+			if lneg {
+				result.setNegAndCheckZero()
+			}
+			return nil
+		}
+		subNeg, err := subAbsUnits(lhs, rhs, result, llay.iu, llay.fu, rlay.iu, rlay.fu)
+		if err != nil {
+			return err
+		}
+		if subNeg != lneg {
+			result.setNegAndCheckZero()
+		}
+		return nil
+	case vecMul:
+		if !uniform {
+			return DecimalMul(lhs, rhs, result)
+		}
+		if lhs.IsZero() || rhs.IsZero() {
+			result.SetZero()
+			return nil
+		}
+		resultNeg := lhs.IsNeg() != rhs.IsNeg()
+		if err := mulAbsUnits(lhs, rhs, result, llay.iu, llay.fu, rlay.iu, rlay.fu); err != nil {
+			return err
+		}
+		if resultNeg {
+			result.setNegAndCheckZero()
+		}
+		return nil
+	case vecDiv:
+		return DecimalDiv(lhs, rhs, result, incrFrac)
+	default:
+		return DecimalMod(lhs, rhs, result)
+	}
+}
+
+func compareVec(lhs, rhs []FixedDecimal, rscalar *FixedDecimal, op CmpOp, bitmap []byte, sel []int) error {
+	n := vecRows(lhs, rhs, nil, rscalar)
+	if n < 0 || len(bitmap) < (n+7)/8 || op > CmpGe {
+		return DecErrInvalidValue
+	}
+	llay := columnLayout(lhs, nil, sel)
+	rlay := columnLayout(rhs, rscalar, sel)
+	uniform := llay.uniform && rlay.uniform
+	for i := range bitmap[:(n+7)/8] {
+		bitmap[i] = 0
+	}
+	if sel == nil {
+		for i := 0; i < n; i++ {
+			if cmpRow(op, &lhs[i], vecOperand(rhs, rscalar, i), uniform, &llay, &rlay) {
+				bitmap[i/8] |= 1 << uint(i%8)
+			}
+		}
+		return nil
+	}
+	for _, i := range sel {
+		if cmpRow(op, &lhs[i], vecOperand(rhs, rscalar, i), uniform, &llay, &rlay) {
+			bitmap[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return nil
+}
+
+// cmpRow evaluates predicate on single row.
+func cmpRow(op CmpOp, lhs, rhs *FixedDecimal, uniform bool, llay, rlay *vecLayout) bool {
+	var c int
+	if uniform { // both are finite
+		lneg, rneg := lhs.IsNeg(), rhs.IsNeg()
+		switch {
+		case lneg == rneg:
+			c = cmpAbsLsu(llay.iu, llay.fu, &lhs.lsu, rlay.iu, rlay.fu, &rhs.lsu)
+			if lneg {
+				c = -c
+			}
+		case lhs.allUnitsZero() && rhs.allUnitsZero():
+			c = 0
+		case lneg:
+			c = -1
+		default:
+			c = 1
+		}
+	} else {
+		var ok bool
+		if c, ok = lhs.CompareAny(rhs); !ok {
+			return op == CmpNe
+		}
+	}
+	switch op {
+	case CmpEq:
+		return c == 0
+	case CmpNe:
+		return c != 0
+	case CmpLt:
+		return c < 0
+	case CmpLe:
+		return c <= 0
+	case CmpGt:
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
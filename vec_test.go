@@ -0,0 +1,258 @@
+package fxd
+
+import (
+	"errors"
+	"testing"
+)
+
+func parseColumn(ss ...string) []FixedDecimal {
+	col := make([]FixedDecimal, len(ss))
+	for i, s := range ss {
+		col[i] = parseSpecial(s)
+	}
+	return col
+}
+
+func TestDecimalArithVec(t *testing.T) {
+	columns := [][2][]FixedDecimal{
+		{ // uniform layout
+			parseColumn("1.50", "-2.25", "0.00", "999.99", "-0.01", "123.45"),
+			parseColumn("2.10", "2.25", "-3.10", "0.01", "-0.01", "-100.00"),
+		},
+		{ // mixed layout
+			parseColumn("1.5", "-2.25", "0", "999999999.999", "-0.000001", "12345678901234567890.12"),
+			parseColumn("2", "2.250", "-3.1", "0.001", "-7", "-100"),
+		},
+	}
+	type arith struct {
+		name    string
+		vec     func(lhs, rhs, out []FixedDecimal, sel []int) error
+		generic func(lhs, rhs, result *FixedDecimal) error
+	}
+	ops := []arith{
+		{"add", AddVec, DecimalAdd},
+		{"sub", SubVec, DecimalSub},
+		{"mul", MulVec, DecimalMul},
+		{"div", func(lhs, rhs, out []FixedDecimal, sel []int) error {
+			return DivVec(lhs, rhs, out, sel, DivIncrFrac)
+		}, func(lhs, rhs, result *FixedDecimal) error {
+			return DecimalDiv(lhs, rhs, result, DivIncrFrac)
+		}},
+	}
+	for k, cols := range columns {
+		lhs, rhs := cols[0], cols[1]
+		for _, op := range ops {
+			if op.name == "div" {
+				continue // division by zero is tested separately
+			}
+			out := make([]FixedDecimal, len(lhs))
+			if err := op.vec(lhs, rhs, out, nil); err != nil {
+				t.Fatalf("%v %v failed: %v", op.name, k, err)
+			}
+			for i := range lhs {
+				var expected FixedDecimal
+				if err := op.generic(&lhs[i], &rhs[i], &expected); err != nil {
+					t.Fatalf("%v %v failed: %v", op.name, k, err)
+				}
+				if out[i] != expected {
+					t.Fatalf("%v %v row %v mismatch: actual=%v, expected=%v", op.name, k, i, out[i].ToString(-1), expected.ToString(-1))
+				}
+			}
+		}
+		// division with selection vector skipping zero divisor
+		sel := []int{0, 1, 3, 5}
+		out := make([]FixedDecimal, len(lhs))
+		out[2].FromInt64(42, true)
+		if err := ops[3].vec(lhs, rhs, out, sel); err != nil {
+			t.Fatalf("div %v failed: %v", k, err)
+		}
+		for _, i := range sel {
+			var expected FixedDecimal
+			ops[3].generic(&lhs[i], &rhs[i], &expected)
+			if out[i] != expected {
+				t.Fatalf("div %v row %v mismatch: actual=%v, expected=%v", k, i, out[i].ToString(-1), expected.ToString(-1))
+			}
+		}
+		if out[2].ToString(-1) != "42" || !out[4].allUnitsZero() {
+			t.Fatal("unselected rows are updated")
+		}
+	}
+}
+
+func TestDecimalArithVecScalar(t *testing.T) {
+	lhs := parseColumn("1.50", "-2.25", "0.00", "10.00")
+	scalar := mustParse("0.5")
+	out := make([]FixedDecimal, len(lhs))
+	type tcase struct {
+		name     string
+		fn       func() error
+		expected []string
+	}
+	for _, c := range []tcase{
+		{"add", func() error { return AddVecScalar(lhs, &scalar, out, nil) }, []string{"2.00", "-1.75", "0.5", "10.50"}},
+		{"sub", func() error { return SubVecScalar(lhs, &scalar, out, nil) }, []string{"1.00", "-2.75", "-0.5", "9.50"}},
+		{"rsub", func() error { return SubScalarVec(&scalar, lhs, out, nil) }, []string{"-1.00", "2.75", "0.5", "-9.50"}},
+		{"mul", func() error { return MulVecScalar(lhs, &scalar, out, nil) }, []string{"0.750", "-1.125", "0", "5.000"}},
+		{"div", func() error { return DivVecScalar(lhs, &scalar, out, nil, DivIncrFrac) }, []string{"3", "-4.5", "0", "20"}},
+		{"mod", func() error { return ModVecScalar(lhs, &scalar, out, nil) }, []string{"0", "-0.25", "0", "0"}},
+	} {
+		if err := c.fn(); err != nil {
+			t.Fatalf("%v failed: %v", c.name, err)
+		}
+		for i, s := range c.expected {
+			expected := mustParse(s)
+			if !out[i].Equal(&expected) {
+				t.Fatalf("%v row %v mismatch: actual=%v, expected=%v", c.name, i, out[i].ToString(-1), s)
+			}
+		}
+	}
+	// scalar aliases output
+	out = parseColumn("1", "2", "3")
+	if err := AddVecScalar(out, &out[0], out, nil); err != nil || out[2].ToString(-1) != "4" {
+		t.Fatalf("failed %v", out[2].ToString(-1))
+	}
+}
+
+func TestDecimalArithVecError(t *testing.T) {
+	lhs := parseColumn("1", "2", "3", "4")
+	rhs := parseColumn("1", "2", "0", "0")
+	out := make([]FixedDecimal, len(lhs))
+	err := DivVec(lhs, rhs, out, nil, DivIncrFrac)
+	var vecErr *VecError
+	if !errors.As(err, &vecErr) || vecErr.Row != 2 || !errors.Is(err, DecErrDivisionByZero) {
+		t.Fatalf("failed %v", err)
+	}
+	if err.Error() != "row 2: decimal division by 0" {
+		t.Fatalf("failed %v", err.Error())
+	}
+	if out[1].CompareInt64(1) != 0 {
+		t.Fatal("rows before failure are not written")
+	}
+	err = DivVec(lhs, rhs, out, []int{3, 0}, DivIncrFrac)
+	if !errors.As(err, &vecErr) || vecErr.Row != 3 {
+		t.Fatalf("failed %v", err)
+	}
+	zero := mustParse("0")
+	err = DivVecScalar(lhs, &zero, out, []int{1, 2}, DivIncrFrac)
+	if !errors.As(err, &vecErr) || vecErr.Row != 1 {
+		t.Fatalf("failed %v", err)
+	}
+	if err := AddVec(lhs, rhs[:3], out, nil); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+	if err := AddVec(lhs, rhs, out[:3], nil); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func TestDecimalCompareVec(t *testing.T) {
+	lhs := parseColumn("1.00", "-2.00", "3.00", "0.00", "5.00", "-6.00", "7.00", "8.00", "9.00", "-0.00")
+	rhs := parseColumn("1.00", "2.00", "-3.00", "0.00", "5.01", "-6.01", "7.00", "-8.00", "9.10", "0.00")
+	mixed := parseColumn("1", "2.0", "NaN", "Inf", "-Inf", "-6.01", "7", "-8", "9.1", "0")
+	type tcase struct {
+		op       CmpOp
+		expected string
+	}
+	for k, r := range [][]FixedDecimal{rhs, mixed} {
+		for _, c := range []tcase{
+			{CmpEq, "1001001001"},
+			{CmpNe, "0110110110"},
+			{CmpLt, "0100100010"},
+			{CmpLe, "1101101011"},
+			{CmpGt, "0010010100"},
+			{CmpGe, "1011011101"},
+		} {
+			expected := c.expected
+			if k == 1 { // row 2 is NaN, row 3 is Inf and row 4 is -Inf
+				switch c.op {
+				case CmpEq, CmpLt, CmpLe, CmpGt, CmpGe:
+					expected = expected[:2] + "0" + expected[3:]
+				}
+				if c.op == CmpEq || c.op == CmpGt || c.op == CmpGe {
+					expected = expected[:3] + "0" + expected[4:]
+				} else {
+					expected = expected[:3] + "1" + expected[4:]
+				}
+				if c.op == CmpNe || c.op == CmpGt || c.op == CmpGe {
+					expected = expected[:4] + "1" + expected[5:]
+				} else {
+					expected = expected[:4] + "0" + expected[5:]
+				}
+			}
+			bitmap := []byte{0xff, 0xff}
+			if err := CompareVec(lhs, r, c.op, bitmap, nil); err != nil {
+				t.Fatalf("failed %v", err)
+			}
+			for i := range lhs {
+				actual := bitmap[i/8]>>uint(i%8)&1 == 1
+				if actual != (expected[i] == '1') {
+					t.Fatalf("compare %v op %v row %v mismatch: actual=%v, expected=%v", k, c.op, i, actual, expected)
+				}
+			}
+			if bitmap[1]&0xfc != 0 {
+				t.Fatal("bits beyond rows are not cleared")
+			}
+		}
+	}
+	// scalar with selection vector
+	three := mustParse("3")
+	bitmap := make([]byte, 2)
+	if err := CompareVecScalar(lhs, &three, CmpGe, bitmap, []int{0, 2, 4, 5, 8}); err != nil {
+		t.Fatalf("failed %v", err)
+	}
+	sel := BitmapToSel(nil, bitmap, len(lhs))
+	if len(sel) != 3 || sel[0] != 2 || sel[1] != 4 || sel[2] != 8 {
+		t.Fatalf("failed %v", sel)
+	}
+	if err := CompareVec(lhs, rhs, CmpEq, bitmap[:1], nil); err != DecErrInvalidValue {
+		t.Fatalf("failed %v", err)
+	}
+}
+
+func benchColumns(n int) ([]FixedDecimal, []FixedDecimal, []FixedDecimal) {
+	lhs := make([]FixedDecimal, n)
+	rhs := make([]FixedDecimal, n)
+	for i := 0; i < n; i++ {
+		lhs[i].FromInt64(int64(i*7919%100000+1), true)
+		lhs[i].Round(2)
+		rhs[i].FromInt64(int64(i*104729%10000+1), true)
+		rhs[i].Round(2)
+	}
+	return lhs, rhs, make([]FixedDecimal, n)
+}
+
+func BenchmarkDecimalAddVec(b *testing.B) {
+	lhs, rhs, out := benchColumns(1024)
+	for i := 0; i < b.N; i++ {
+		AddVec(lhs, rhs, out, nil)
+	}
+}
+
+func BenchmarkDecimalAddRows(b *testing.B) {
+	lhs, rhs, out := benchColumns(1024)
+	for i := 0; i < b.N; i++ {
+		for j := range lhs {
+			DecimalAdd(&lhs[j], &rhs[j], &out[j])
+		}
+	}
+}
+
+func BenchmarkDecimalCompareVec(b *testing.B) {
+	lhs, rhs, _ := benchColumns(1024)
+	bitmap := make([]byte, 128)
+	for i := 0; i < b.N; i++ {
+		CompareVec(lhs, rhs, CmpLt, bitmap, nil)
+	}
+}
+
+func BenchmarkDecimalCompareRows(b *testing.B) {
+	lhs, rhs, _ := benchColumns(1024)
+	bitmap := make([]byte, 128)
+	for i := 0; i < b.N; i++ {
+		for j := range lhs {
+			if lhs[j].Less(&rhs[j]) {
+				bitmap[j/8] |= 1 << uint(j%8)
+			}
+		}
+	}
+}